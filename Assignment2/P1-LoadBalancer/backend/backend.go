@@ -0,0 +1,203 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/example/protofiles"
+	"google.golang.org/grpc"
+)
+
+// DefaultMaxConcurrentTasks is the threshold for maximum concurrent tasks
+// before a server marks itself as unavailable.
+const DefaultMaxConcurrentTasks = 5
+
+// fibonacci computes the n-th Fibonacci number recursively.
+// Note: This implementation is intentionally inefficient to simulate CPU load.
+func fibonacci(n int) int {
+	if n <= 1 {
+		return n
+	}
+	return fibonacci(n-1) + fibonacci(n-2)
+}
+
+// Server is a backend that computes tasks and reports its load to the LB server.
+type Server struct {
+	pb.UnimplementedBackendServiceServer
+	Addr            string
+	capacity        int32 // concurrent tasks allowed before reporting unavailable
+	concurrentTasks int32
+
+	mu         sync.Mutex
+	slowdown   float64 // multiplier applied to every task's compute time
+	grpcServer *grpc.Server
+	stop       chan struct{}
+	stopped    bool
+}
+
+// NewServer returns a backend server for addr that accepts up to capacity
+// concurrent tasks before reporting itself unavailable.
+func NewServer(addr string, capacity int) *Server {
+	if capacity <= 0 {
+		capacity = DefaultMaxConcurrentTasks
+	}
+	return &Server{
+		Addr:     addr,
+		capacity: int32(capacity),
+		slowdown: 1,
+		stop:     make(chan struct{}),
+	}
+}
+
+// SetSlowdown makes every subsequent task take factor times as long.
+// A factor of 1 restores normal speed.
+func (s *Server) SetSlowdown(factor float64) {
+	if factor < 1 {
+		factor = 1
+	}
+	s.mu.Lock()
+	s.slowdown = factor
+	s.mu.Unlock()
+}
+
+// Load returns the number of tasks currently being processed.
+func (s *Server) Load() int {
+	return int(atomic.LoadInt32(&s.concurrentTasks))
+}
+
+// Compute processes the task request.
+// If the task starts with "fibonacci:", it parses the number and computes the Fibonacci value.
+// Otherwise, it returns a default message.
+// It also updates the concurrent task counter.
+func (s *Server) Compute(ctx context.Context, req *pb.TaskRequest) (*pb.TaskResponse, error) {
+	// Increment concurrent tasks counter.
+	atomic.AddInt32(&s.concurrentTasks, 1)
+	defer atomic.AddInt32(&s.concurrentTasks, -1)
+
+	start := time.Now()
+	task := req.Task
+	parts := strings.Split(task, ":")
+	if len(parts) > 1 {
+		task = strings.TrimSpace(strings.Join(parts[1:], ":"))
+	}
+
+	var result string
+
+	// Check if the task is a Fibonacci computation.
+	if strings.HasPrefix(task, "fibonacci:") {
+		taskParts := strings.Split(task, ":")
+		if len(taskParts) == 2 {
+			n, err := strconv.Atoi(strings.TrimSpace(taskParts[1]))
+			if err != nil {
+				result = "Error: invalid number for fibonacci"
+			} else {
+				fib := fibonacci(n)
+				result = fmt.Sprintf("Fibonacci(%d) = %d", n, fib)
+			}
+		} else {
+			result = "Error: invalid task format"
+		}
+	} else {
+		result = "Error: unknown task"
+	}
+
+	// Simulate a slower machine by stretching the time spent on the task.
+	s.mu.Lock()
+	slowdown := s.slowdown
+	s.mu.Unlock()
+	if slowdown > 1 {
+		time.Sleep(time.Duration(float64(time.Since(start)) * (slowdown - 1)))
+	}
+
+	return &pb.TaskResponse{Result: result}, nil
+}
+
+// Serve registers the server with the LB server, starts periodic load reports
+// and serves gRPC requests on lis until Stop is called.
+func (s *Server) Serve(lis net.Listener, lbAddress string, reportInterval time.Duration) error {
+	// Connect to LB server for registration.
+	conn, err := grpc.Dial(lbAddress, grpc.WithInsecure())
+	if err != nil {
+		return fmt.Errorf("failed to connect to LB: %v", err)
+	}
+	lbClient := pb.NewLoadBalancerClient(conn)
+	_, err = lbClient.RegisterServer(context.Background(), &pb.ServerInfo{Address: s.Addr})
+	if err != nil {
+		log.Printf("Server %s: registration error: %v", s.Addr, err)
+	}
+	conn.Close()
+
+	go s.reportLoad(lbAddress, reportInterval)
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.grpcServer = grpc.NewServer()
+	pb.RegisterBackendServiceServer(s.grpcServer, s)
+	s.mu.Unlock()
+	log.Printf("Backend server running on %s", s.Addr)
+	return s.grpcServer.Serve(lis)
+}
+
+// Stop simulates a crash: the server stops serving and stops reporting load.
+// The LB server keeps its last reported status, as it would for a real crash.
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	close(s.stop)
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+}
+
+// reportLoad periodically reports the true load and availability.
+func (s *Server) reportLoad(lbAddress string, interval time.Duration) {
+	for {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		currentLoad := s.Load()
+		if currentLoad != 0 {
+			log.Printf("%s has Current Load %d:", s.Addr, currentLoad)
+		}
+		available := currentLoad < int(s.capacity)
+
+		conn, err := grpc.Dial(lbAddress, grpc.WithInsecure())
+		if err != nil {
+			log.Printf("Server %s: failed to reconnect to LB: %v", s.Addr, err)
+		} else {
+			lbClient := pb.NewLoadBalancerClient(conn)
+			_, err = lbClient.ReportLoad(context.Background(), &pb.ServerLoad{
+				Address:   s.Addr,
+				Load:      int32(currentLoad),
+				Available: available,
+			})
+			if err != nil {
+				log.Printf("Server %s: report load error: %v", s.Addr, err)
+			}
+			conn.Close()
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package balancer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	pb "github.com/example/protofiles"
+)
+
+type LoadBalancer struct {
+	pb.UnimplementedLoadBalancerServer
+	rrIndex  int // for round-robin selection
+	registry Registry
+	mu       sync.Mutex // protects rrIndex
+}
+
+// New returns a LoadBalancer that tracks backend servers in the given registry.
+func New(registry Registry) *LoadBalancer {
+	return &LoadBalancer{registry: registry}
+}
+
+// RegisterServer records the server as available with no load.
+func (lb *LoadBalancer) RegisterServer(ctx context.Context, req *pb.ServerInfo) (*pb.RegisterResponse, error) {
+	status := ServerStatus{
+		Address:   req.Address,
+		Load:      0,
+		Available: true,
+	}
+	if err := lb.registry.Put(ctx, status); err != nil {
+		return nil, err
+	}
+	log.Printf("Registered server: %s\n", req.Address)
+	return &pb.RegisterResponse{Message: "Registered successfully"}, nil
+}
+
+// ReportLoad updates a server’s load and availability in the registry.
+func (lb *LoadBalancer) ReportLoad(ctx context.Context, req *pb.ServerLoad) (*pb.LoadResponse, error) {
+	status := ServerStatus{
+		Address:   req.Address,
+		Load:      int(req.Load),
+		Available: req.Available,
+	}
+	if err := lb.registry.Put(ctx, status); err != nil {
+		return nil, fmt.Errorf("failed to update server status: %v", err)
+	}
+	log.Printf("Updated server %s: load=%d, available=%v\n", req.Address, req.Load, req.Available)
+	return &pb.LoadResponse{Message: "Load updated"}, nil
+}
+
+// GetBestServer lists all backend servers and selects one based on the requested strategy.
+func (lb *LoadBalancer) GetBestServer(ctx context.Context, req *pb.BalanceRequest) (*pb.ServerInfo, error) {
+	servers, err := lb.registry.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var availableServers []ServerStatus
+	for _, status := range servers {
+		if status.Available {
+			availableServers = append(availableServers, status)
+		}
+	}
+	if len(availableServers) == 0 {
+		return nil, fmt.Errorf("no available servers")
+	}
+
+	switch req.Strategy {
+	case pb.LoadBalanceStrategy_PICK_FIRST:
+		selected := availableServers[0]
+		log.Printf("[PICK_FIRST] Selected server: %s with load: %d", selected.Address, selected.Load)
+		return &pb.ServerInfo{Address: selected.Address}, nil
+
+	case pb.LoadBalanceStrategy_ROUND_ROBIN:
+		lb.mu.Lock()
+		if lb.rrIndex >= len(availableServers) {
+			lb.rrIndex = 0
+		}
+
+		selected := availableServers[lb.rrIndex%len(availableServers)]
+		lb.rrIndex++
+		lb.mu.Unlock()
+		log.Printf("[ROUND_ROBIN] Selected server: %s with load: %d", selected.Address, selected.Load)
+		return &pb.ServerInfo{Address: selected.Address}, nil
+
+	case pb.LoadBalanceStrategy_LEAST_LOAD:
+		best := availableServers[0]
+		for _, s := range availableServers {
+			if s.Load < best.Load {
+				best = s
+			}
+		}
+		return &pb.ServerInfo{Address: best.Address}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown strategy")
+	}
+}
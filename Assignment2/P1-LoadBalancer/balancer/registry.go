@@ -0,0 +1,100 @@
+package balancer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const etcdServersPrefix = "/lb/servers/"
+
+// ServerStatus is the last known state of a backend server.
+type ServerStatus struct {
+	Address   string `json:"address"`
+	Load      int    `json:"load"`
+	Available bool   `json:"available"`
+}
+
+// Registry stores backend server statuses for the load balancer.
+// List returns servers ordered by address, matching etcd's key order.
+type Registry interface {
+	Put(ctx context.Context, status ServerStatus) error
+	List(ctx context.Context) ([]ServerStatus, error)
+}
+
+// EtcdRegistry keeps server statuses as JSON values under etcdServersPrefix.
+type EtcdRegistry struct {
+	client *clientv3.Client
+}
+
+// NewEtcdRegistry returns a registry backed by the given etcd client.
+func NewEtcdRegistry(client *clientv3.Client) *EtcdRegistry {
+	return &EtcdRegistry{client: client}
+}
+
+// Put writes the server's JSON status into etcd.
+func (r *EtcdRegistry) Put(ctx context.Context, status ServerStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %v", err)
+	}
+	if _, err := r.client.Put(ctx, etcdServersPrefix+status.Address, string(data)); err != nil {
+		return fmt.Errorf("failed to put key in etcd: %v", err)
+	}
+	return nil
+}
+
+// List queries etcd for all keys with the servers prefix.
+func (r *EtcdRegistry) List(ctx context.Context) ([]ServerStatus, error) {
+	resp, err := r.client.Get(ctx, etcdServersPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query etcd: %v", err)
+	}
+	var servers []ServerStatus
+	for _, kv := range resp.Kvs {
+		var status ServerStatus
+		if err := json.Unmarshal(kv.Value, &status); err != nil {
+			log.Printf("failed to unmarshal key %s: %v", string(kv.Key), err)
+			continue
+		}
+		servers = append(servers, status)
+	}
+	return servers, nil
+}
+
+// MemoryRegistry is an in-process Registry used when etcd is not available,
+// e.g. by the scenario runner.
+type MemoryRegistry struct {
+	mu      sync.Mutex
+	servers map[string]ServerStatus
+}
+
+// NewMemoryRegistry returns an empty in-memory registry.
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{servers: make(map[string]ServerStatus)}
+}
+
+// Put records the server's status.
+func (r *MemoryRegistry) Put(ctx context.Context, status ServerStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.servers[status.Address] = status
+	return nil
+}
+
+// List returns all recorded statuses ordered by address.
+func (r *MemoryRegistry) List(ctx context.Context) ([]ServerStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	servers := make([]ServerStatus, 0, len(r.servers))
+	for _, s := range r.servers {
+		servers = append(servers, s)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Address < servers[j].Address })
+	return servers, nil
+}
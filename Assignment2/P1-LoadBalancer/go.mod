@@ -6,6 +6,7 @@ require (
 	go.etcd.io/etcd/client/v3 v3.6.0
 	google.golang.org/grpc v1.72.1
 	google.golang.org/protobuf v1.36.6
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+func main() {
+	scenarioPath := flag.String("scenario", "loadtest/scenarios/heterogeneous.yaml", "Scenario file (YAML or JSON)")
+	outPath := flag.String("out", "", "Also write the comparison table to this file")
+	seed := flag.Int64("seed", 0, "Override the scenario's random seed")
+	verbose := flag.Bool("v", false, "Show LB and backend logs")
+	flag.Parse()
+
+	sc, err := loadScenario(*scenarioPath)
+	if err != nil {
+		log.Fatalf("Failed to load scenario: %v", err)
+	}
+	if *seed != 0 {
+		sc.Seed = *seed
+	}
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	var results []*Result
+	for _, strategy := range sc.Strategies {
+		fmt.Printf("Running %s for %v with %d clients (seed %d)...\n", strategy, sc.Duration, sc.Clients, sc.Seed)
+		res, err := runStrategy(sc, strategy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Strategy %s failed: %v\n", strategy, err)
+			os.Exit(1)
+		}
+		results = append(results, res)
+	}
+
+	table := formatResults(sc, results)
+	fmt.Print(table)
+	if *outPath != "" {
+		if err := os.WriteFile(*outPath, []byte(table), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *outPath, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// formatResults renders the strategy comparison and per-backend distribution as markdown tables.
+func formatResults(sc *Scenario, results []*Result) string {
+	var b strings.Builder
+	name := sc.Name
+	if name == "" {
+		name = "scenario"
+	}
+	fmt.Fprintf(&b, "\n## %s (seed %d, %d clients, %v)\n\n", name, sc.Seed, sc.Clients, sc.Duration)
+	b.WriteString("Strategy | Total Requests | Errors | Avg Latency (ms) | p95 (ms) | p99 (ms) | Throughput (req/sec)\n")
+	b.WriteString("---------|----------------|--------|------------------|----------|----------|---------------------\n")
+	for _, r := range results {
+		fmt.Fprintf(&b, "%s | %d | %d | %.2f | %.2f | %.2f | %.2f\n",
+			r.Strategy, r.Requests, r.Errors, ms(r.AvgLatency), ms(r.P95Latency), ms(r.P99Latency), r.Throughput)
+	}
+
+	labels := make(map[string]bool)
+	for _, r := range results {
+		for label := range r.PerBackend {
+			labels[label] = true
+		}
+	}
+	var sorted []string
+	for label := range labels {
+		sorted = append(sorted, label)
+	}
+	sort.Strings(sorted)
+
+	b.WriteString("\nBackend")
+	for _, r := range results {
+		fmt.Fprintf(&b, " | %s", r.Strategy)
+	}
+	b.WriteString("\n--------")
+	for range results {
+		b.WriteString("|------")
+	}
+	b.WriteString("\n")
+	for _, label := range sorted {
+		b.WriteString(label)
+		for _, r := range results {
+			fmt.Fprintf(&b, " | %d", r.PerBackend[label])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/backend"
+	"github.com/example/balancer"
+	pb "github.com/example/protofiles"
+	"google.golang.org/grpc"
+)
+
+// Result holds the measurements of one strategy run.
+type Result struct {
+	Strategy   string
+	Requests   int64
+	Errors     int64
+	AvgLatency time.Duration
+	P95Latency time.Duration
+	P99Latency time.Duration
+	Throughput float64
+	PerBackend map[string]int64 // completed requests by backend label
+}
+
+// instance is one running backend server of the scenario.
+type instance struct {
+	label  string // "name" or "name[i]" for groups
+	group  string
+	server *backend.Server
+}
+
+// runStrategy starts an LB server and the scenario's backends in-process,
+// drives the configured clients with the given strategy and tears everything down.
+func runStrategy(sc *Scenario, strategy string) (*Result, error) {
+	// Load balancer backed by an in-memory registry instead of etcd.
+	lbListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for LB: %v", err)
+	}
+	lbAddress := lbListener.Addr().String()
+	registry := balancer.NewMemoryRegistry()
+	lbServer := grpc.NewServer()
+	pb.RegisterLoadBalancerServer(lbServer, balancer.New(registry))
+	go lbServer.Serve(lbListener)
+	defer lbServer.Stop()
+
+	// Backend servers on ephemeral ports.
+	var instances []*instance
+	byAddr := make(map[string]string)
+	for _, spec := range sc.Backends {
+		for i := 0; i < spec.Count; i++ {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return nil, fmt.Errorf("failed to listen for backend %s: %v", spec.Name, err)
+			}
+			addr := lis.Addr().String()
+			srv := backend.NewServer(addr, spec.Capacity)
+			srv.SetSlowdown(spec.Slowdown)
+			label := spec.Name
+			if spec.Count > 1 {
+				label = fmt.Sprintf("%s[%d]", spec.Name, i)
+			}
+			instances = append(instances, &instance{label: label, group: spec.Name, server: srv})
+			byAddr[addr] = label
+			go func() {
+				if err := srv.Serve(lis, lbAddress, sc.ReportInterval.Duration); err != nil {
+					log.Printf("Backend %s: serve error: %v", label, err)
+				}
+			}()
+		}
+	}
+	defer func() {
+		for _, inst := range instances {
+			inst.server.Stop()
+		}
+	}()
+	if err := waitForRegistration(registry, len(instances)); err != nil {
+		return nil, err
+	}
+
+	// Injected faults, relative to the start of the run.
+	var timers []*time.Timer
+	for _, e := range sc.Events {
+		targets := eventTargets(instances, e.Backend)
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("event at %v: unknown backend %q", e.At, e.Backend)
+		}
+		e := e
+		timers = append(timers, time.AfterFunc(e.At.Duration, func() {
+			for _, inst := range targets {
+				switch e.Action {
+				case "slowdown":
+					log.Printf("Event: slowing down %s by %.1fx", inst.label, e.Factor)
+					inst.server.SetSlowdown(e.Factor)
+				case "crash":
+					log.Printf("Event: crashing %s", inst.label)
+					inst.server.Stop()
+				}
+			}
+		}))
+	}
+	defer func() {
+		for _, t := range timers {
+			t.Stop()
+		}
+	}()
+
+	return runClients(sc, strategy, lbAddress, byAddr)
+}
+
+// runClients launches the scenario's clients against the LB server and aggregates their results.
+func runClients(sc *Scenario, strategy, lbAddress string, byAddr map[string]string) (*Result, error) {
+	lbConn, err := grpc.Dial(lbAddress, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Load Balancer: %v", err)
+	}
+	defer lbConn.Close()
+	lbClient := pb.NewLoadBalancerClient(lbConn)
+
+	res := &Result{Strategy: strategy, PerBackend: make(map[string]int64)}
+	var mu sync.Mutex
+	var latencies []time.Duration
+	var wg sync.WaitGroup
+	stopTime := time.Now().Add(sc.Duration.Duration)
+
+	for i := 0; i < sc.Clients; i++ {
+		wg.Add(1)
+		go func(clientID int) {
+			defer wg.Done()
+			// Every client draws its tasks from its own seeded source, so each
+			// strategy sees the same sequence of task sizes.
+			rng := rand.New(rand.NewSource(sc.Seed + int64(clientID)))
+			var local []time.Duration
+			var errors int64
+			perBackend := make(map[string]int64)
+			for time.Now().Before(stopTime) {
+				n := sc.Workload.MinN + rng.Intn(sc.Workload.MaxN-sc.Workload.MinN+1)
+				latency, addr, err := sendTask(lbClient, strategies[strategy], clientID, n)
+				if err != nil {
+					errors++
+				} else {
+					local = append(local, latency)
+					perBackend[byAddr[addr]]++
+				}
+				time.Sleep(sc.Workload.ThinkTime.Duration)
+			}
+			mu.Lock()
+			latencies = append(latencies, local...)
+			res.Errors += errors
+			for label, count := range perBackend {
+				res.PerBackend[label] += count
+			}
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	res.Requests = int64(len(latencies))
+	res.Throughput = float64(res.Requests) / sc.Duration.Seconds()
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		var total time.Duration
+		for _, l := range latencies {
+			total += l
+		}
+		res.AvgLatency = total / time.Duration(len(latencies))
+		res.P95Latency = percentile(latencies, 0.95)
+		res.P99Latency = percentile(latencies, 0.99)
+	}
+	return res, nil
+}
+
+// sendTask asks the LB server for a backend and runs one fibonacci task on it.
+func sendTask(lbClient pb.LoadBalancerClient, strategy pb.LoadBalanceStrategy, clientID, n int) (time.Duration, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	serverInfo, err := lbClient.GetBestServer(ctx, &pb.BalanceRequest{Strategy: strategy})
+	if err != nil {
+		return 0, "", err
+	}
+	backendConn, err := grpc.Dial(serverInfo.Address, grpc.WithInsecure())
+	if err != nil {
+		return 0, "", err
+	}
+	defer backendConn.Close()
+	backendClient := pb.NewBackendServiceClient(backendConn)
+
+	start := time.Now()
+	_, err = backendClient.Compute(ctx, &pb.TaskRequest{Task: fmt.Sprintf("Client %d: fibonacci:%d", clientID, n)})
+	if err != nil {
+		return 0, serverInfo.Address, err
+	}
+	return time.Since(start), serverInfo.Address, nil
+}
+
+// waitForRegistration blocks until all backends have registered with the LB server.
+func waitForRegistration(registry *balancer.MemoryRegistry, want int) error {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		servers, _ := registry.List(context.Background())
+		if len(servers) >= want {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("timed out waiting for %d backends to register", want)
+}
+
+// eventTargets resolves an event's backend name to the instances it applies to.
+func eventTargets(instances []*instance, name string) []*instance {
+	var targets []*instance
+	for _, inst := range instances {
+		if inst.label == name || (inst.group == name && !strings.Contains(name, "[")) {
+			targets = append(targets, inst)
+		}
+	}
+	return targets
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx]
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+)
+
+// TestRunStrategy runs round robin over two backends registered in the
+// runner's in-memory registry and crashes one of them part-way through. Both
+// serve requests before the crash; after it, the crashed backend stays
+// registered, so the LB server keeps handing it out and its requests fail.
+func TestRunStrategy(t *testing.T) {
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+	sc := &Scenario{
+		Duration:       Duration{1500 * time.Millisecond},
+		ReportInterval: Duration{100 * time.Millisecond},
+		Clients:        2,
+		Strategies:     []string{"round_robin"},
+		Workload:       Workload{MinN: 5, MaxN: 10, ThinkTime: Duration{10 * time.Millisecond}},
+		Backends:       []BackendSpec{{Name: "a"}, {Name: "b"}},
+		Events:         []Event{{At: Duration{500 * time.Millisecond}, Backend: "b", Action: "crash"}},
+	}
+	if err := sc.validate(); err != nil {
+		t.Fatal(err)
+	}
+	res, err := runStrategy(sc, "round_robin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.PerBackend["a"] == 0 || res.PerBackend["b"] == 0 {
+		t.Errorf("requests by backend %v, want both backends used", res.PerBackend)
+	}
+	if res.Errors == 0 {
+		t.Error("no failed requests after b crashed, want it to stay registered")
+	}
+	if res.Requests != res.PerBackend["a"]+res.PerBackend["b"] {
+		t.Errorf("%d requests, but %v by backend", res.Requests, res.PerBackend)
+	}
+}
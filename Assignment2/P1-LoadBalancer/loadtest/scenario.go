@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	pb "github.com/example/protofiles"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario describes one reproducible load-balancer experiment. It is read
+// from YAML; since YAML is a superset of JSON, JSON files work as well.
+type Scenario struct {
+	Name           string        `yaml:"name"`
+	Seed           int64         `yaml:"seed"`
+	Duration       Duration      `yaml:"duration"`
+	ReportInterval Duration      `yaml:"report_interval"`
+	Clients        int           `yaml:"clients"`
+	Strategies     []string      `yaml:"strategies"`
+	Workload       Workload      `yaml:"workload"`
+	Backends       []BackendSpec `yaml:"backends"`
+	Events         []Event       `yaml:"events"`
+}
+
+// Workload controls the tasks each client sends.
+type Workload struct {
+	MinN      int      `yaml:"min_n"`      // smallest fibonacci argument
+	MaxN      int      `yaml:"max_n"`      // largest fibonacci argument
+	ThinkTime Duration `yaml:"think_time"` // pause between requests
+}
+
+// BackendSpec describes one group of identical backend servers.
+type BackendSpec struct {
+	Name     string  `yaml:"name"`
+	Count    int     `yaml:"count"`    // number of servers in the group, default 1
+	Capacity int     `yaml:"capacity"` // concurrent tasks before reporting unavailable
+	Slowdown float64 `yaml:"slowdown"` // compute time multiplier, default 1
+}
+
+// Event is a fault injected into a backend at a fixed offset from the start of a run.
+// A crashed backend stops serving and reporting load, but stays registered
+// with its last reported status, as it would with a real crash, so the LB
+// server keeps handing it out and its requests fail.
+type Event struct {
+	At      Duration `yaml:"at"`
+	Backend string   `yaml:"backend"` // backend name, "name[i]" for one server of a group
+	Action  string   `yaml:"action"`  // "slowdown" or "crash"
+	Factor  float64  `yaml:"factor"`  // slowdown multiplier, 1 restores normal speed
+}
+
+// Duration is a time.Duration written with a unit, such as "30s" or "100ms".
+// Bare numbers are rejected; YAML would otherwise read them as nanoseconds.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!str" {
+		return fmt.Errorf("line %d: duration %s needs a unit, e.g. \"30s\"", node.Line, node.Value)
+	}
+	v, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %v", node.Line, err)
+	}
+	d.Duration = v
+	return nil
+}
+
+var strategies = map[string]pb.LoadBalanceStrategy{
+	"pick_first":  pb.LoadBalanceStrategy_PICK_FIRST,
+	"round_robin": pb.LoadBalanceStrategy_ROUND_ROBIN,
+	"least_load":  pb.LoadBalanceStrategy_LEAST_LOAD,
+}
+
+// loadScenario reads a scenario file and fills in defaults.
+func loadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sc := &Scenario{}
+	if err := yaml.Unmarshal(data, sc); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %v", path, err)
+	}
+	if err := sc.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %v", path, err)
+	}
+	return sc, nil
+}
+
+func (sc *Scenario) validate() error {
+	if sc.Seed == 0 {
+		sc.Seed = 1
+	}
+	for _, d := range []struct {
+		name  string
+		value Duration
+	}{
+		{"duration", sc.Duration},
+		{"report_interval", sc.ReportInterval},
+		{"think_time", sc.Workload.ThinkTime},
+	} {
+		if d.value.Duration < 0 {
+			return fmt.Errorf("%s is negative: %v", d.name, d.value)
+		}
+	}
+	if sc.Duration.Duration == 0 {
+		sc.Duration.Duration = 30 * time.Second
+	}
+	if sc.ReportInterval.Duration == 0 {
+		sc.ReportInterval.Duration = time.Second
+	}
+	if sc.Clients <= 0 {
+		sc.Clients = 4
+	}
+	if len(sc.Strategies) == 0 {
+		sc.Strategies = []string{"pick_first", "round_robin", "least_load"}
+	}
+	for _, s := range sc.Strategies {
+		if _, ok := strategies[s]; !ok {
+			return fmt.Errorf("unknown strategy %q", s)
+		}
+	}
+	if sc.Workload.MinN <= 0 {
+		sc.Workload.MinN = 30
+	}
+	if sc.Workload.MaxN < sc.Workload.MinN {
+		sc.Workload.MaxN = sc.Workload.MinN
+	}
+	if len(sc.Backends) == 0 {
+		return fmt.Errorf("no backends")
+	}
+	names := make(map[string]bool)
+	for i := range sc.Backends {
+		b := &sc.Backends[i]
+		if b.Name == "" {
+			b.Name = fmt.Sprintf("backend%d", i)
+		}
+		if names[b.Name] {
+			return fmt.Errorf("duplicate backend name %q", b.Name)
+		}
+		names[b.Name] = true
+		if b.Count <= 0 {
+			b.Count = 1
+		}
+		if b.Slowdown < 1 {
+			b.Slowdown = 1
+		}
+	}
+	for _, e := range sc.Events {
+		if e.Action != "slowdown" && e.Action != "crash" {
+			return fmt.Errorf("unknown event action %q", e.Action)
+		}
+		if e.At.Duration < 0 {
+			return fmt.Errorf("event %s of %s is at a negative offset: %v", e.Action, e.Backend, e.At)
+		}
+	}
+	sort.SliceStable(sc.Events, func(i, j int) bool { return sc.Events[i].At.Duration < sc.Events[j].At.Duration })
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadScenario(t *testing.T) {
+	const backends = "backends:\n  - name: a\n"
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string // substring of the error, "" if the scenario is valid
+		check   func(t *testing.T, sc *Scenario)
+	}{
+		{
+			name: "defaults",
+			data: backends,
+			check: func(t *testing.T, sc *Scenario) {
+				if sc.Duration.Duration != 30*time.Second || sc.ReportInterval.Duration != time.Second {
+					t.Errorf("duration %v, report interval %v, want 30s and 1s", sc.Duration, sc.ReportInterval)
+				}
+				if sc.Seed != 1 || sc.Clients != 4 || len(sc.Strategies) != 3 {
+					t.Errorf("seed %d, %d clients, strategies %v, want 1, 4 and all three", sc.Seed, sc.Clients, sc.Strategies)
+				}
+				if b := sc.Backends[0]; b.Count != 1 || b.Slowdown != 1 {
+					t.Errorf("backend count %d, slowdown %v, want 1 and 1", b.Count, b.Slowdown)
+				}
+			},
+		},
+		{
+			name: "durations with units",
+			data: "duration: 2m\nreport_interval: 500ms\nworkload:\n  think_time: 10ms\n" + backends +
+				"events:\n  - {at: 20s, backend: a, action: crash}\n  - {at: 5s, backend: a, action: slowdown, factor: 2}\n",
+			check: func(t *testing.T, sc *Scenario) {
+				if sc.Duration.Duration != 2*time.Minute || sc.ReportInterval.Duration != 500*time.Millisecond || sc.Workload.ThinkTime.Duration != 10*time.Millisecond {
+					t.Errorf("durations %v, %v, %v, want 2m, 500ms and 10ms", sc.Duration, sc.ReportInterval, sc.Workload.ThinkTime)
+				}
+				if sc.Events[0].Action != "slowdown" || sc.Events[1].Action != "crash" {
+					t.Errorf("events %+v are not in time order", sc.Events)
+				}
+			},
+		},
+		{
+			name: "JSON",
+			data: `{"duration": "10s", "clients": 2, "backends": [{"name": "a", "count": 3}]}`,
+			check: func(t *testing.T, sc *Scenario) {
+				if sc.Duration.Duration != 10*time.Second || sc.Clients != 2 || sc.Backends[0].Count != 3 {
+					t.Errorf("got %+v", sc)
+				}
+			},
+		},
+		{
+			name: "zero duration",
+			data: "duration: 0s\n" + backends,
+			check: func(t *testing.T, sc *Scenario) {
+				if sc.Duration.Duration != 30*time.Second {
+					t.Errorf("duration %v, want the default of 30s", sc.Duration)
+				}
+			},
+		},
+		{name: "bad yaml", data: "backends: [\n", wantErr: "failed to parse"},
+		{name: "bare number duration", data: "duration: 30\n" + backends, wantErr: "needs a unit"},
+		{name: "bare number in JSON", data: `{"duration": 30, "backends": [{"name": "a"}]}`, wantErr: "needs a unit"},
+		{name: "quoted number duration", data: "duration: \"30\"\n" + backends, wantErr: "missing unit"},
+		{name: "bare number event offset", data: backends + "events:\n  - {at: 5, backend: a, action: crash}\n", wantErr: "needs a unit"},
+		{name: "negative duration", data: "duration: -5s\n" + backends, wantErr: "duration is negative"},
+		{name: "negative think time", data: "workload:\n  think_time: -1ms\n" + backends, wantErr: "think_time is negative"},
+		{name: "negative event offset", data: backends + "events:\n  - {at: -1s, backend: a, action: crash}\n", wantErr: "negative offset"},
+		{name: "unknown action", data: backends + "events:\n  - {at: 1s, backend: a, action: explode}\n", wantErr: `unknown event action "explode"`},
+		{name: "unknown strategy", data: "strategies: [random]\n" + backends, wantErr: `unknown strategy "random"`},
+		{name: "no backends", data: "duration: 1s\n", wantErr: "no backends"},
+		{name: "duplicate backend", data: backends + "  - name: a\n", wantErr: `duplicate backend name "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scenario.yaml")
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			sc, err := loadScenario(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, sc)
+		})
+	}
+}
+
+// TestExampleScenarios checks that the scenarios shipped with the runner load.
+func TestExampleScenarios(t *testing.T) {
+	paths, err := filepath.Glob("scenarios/*")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no scenarios: %v", err)
+	}
+	for _, path := range paths {
+		if _, err := loadScenario(path); err != nil {
+			t.Error(err)
+		}
+	}
+}
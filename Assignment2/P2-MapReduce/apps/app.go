@@ -0,0 +1,168 @@
+package apps
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// KeyValue is one intermediate pair emitted by a map function.
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// MapReduceApp is a user-defined MapReduce job. Map is called once per record
+// of the job's input format, e.g. once per line of text input or once per
+// file of whole_file input, with filename the file the record comes from.
+// Reduce is called once per distinct key with every value emitted for it.
+type MapReduceApp interface {
+	Map(filename string, contents string) []KeyValue
+	Reduce(key string, values []string) string
+}
+
+// Combiner is implemented by apps whose values can be partially reduced on
+// the map side. Combine gets the values one map task emitted for a key and
+// returns the (usually fewer) values to write instead; Reduce must give the
+// same result for the combined values as for the original ones.
+type Combiner interface {
+	Combine(key string, values []string) []string
+}
+
+// Configurable is implemented by apps that take job parameters, given as
+// key=value pairs when the job is submitted. Configure returns the app to run
+// with the given parameters, or an error if one is unknown or invalid.
+type Configurable interface {
+	Configure(params map[string]string) (MapReduceApp, error)
+}
+
+// Params holds an app's parameters. It is a flag.Value that takes one
+// key=value pair per use of the flag.
+type Params map[string]string
+
+func (p Params) String() string {
+	pairs := make([]string, 0, len(p))
+	for k, v := range p {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (p Params) Set(pair string) error {
+	k, v, ok := strings.Cut(pair, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("parameter %q is not of the form key=value", pair)
+	}
+	p[k] = v
+	return nil
+}
+
+// InputFormatter is implemented by apps that need their input in a format
+// other than text, e.g. whole files. It is used when a job names no format,
+// and a job that names another one is rejected; only a codec suffix such as
+// "+gzip" may be added.
+type InputFormatter interface {
+	InputFormat() string
+}
+
+// PartitioningApp is implemented by apps that need a partitioner other than
+// hash, e.g. range partitioning for totally ordered output. It is used when
+// a job names no partitioner.
+type PartitioningApp interface {
+	Partitioning() string
+}
+
+// RecordReducer is implemented by apps whose reduce function writes any
+// number of output records for a key, e.g. one per input record. The worker
+// calls ReduceRecords instead of Reduce and writes the pairs it returns in
+// order.
+type RecordReducer interface {
+	ReduceRecords(key string, values []string) []KeyValue
+}
+
+// JSONValuer is implemented by apps whose reduce results are JSON, such as
+// the file list of the inverted index. Output formats that hold JSON, such as
+// json_lines, embed the results of these apps as they are and write those of
+// any other app as strings, even if they happen to be valid JSON.
+type JSONValuer interface {
+	JSONValues() bool
+}
+
+// StreamReducer is implemented by apps that reduce the values of a key one at
+// a time, so a key with more values than fit in a reducer's memory is reduced
+// even without a combiner. The worker calls ReduceStream instead of Reduce
+// and ReduceRecords, with values read from disk as they are ranged over, and
+// writes the pairs passed to emit in order. Values not ranged over are
+// skipped.
+type StreamReducer interface {
+	ReduceStream(key string, values iter.Seq[string], emit func(KeyValue))
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]MapReduceApp)
+)
+
+// Register makes an app available under name. It is meant for the init
+// functions of built-in apps and panics if the name is taken.
+func Register(name string, app MapReduceApp) {
+	if err := register(name, app); err != nil {
+		panic(fmt.Sprintf("apps: %v", err))
+	}
+}
+
+// register makes an app available under name unless the name is taken.
+func register(name string, app MapReduceApp) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("%q registered twice", name)
+	}
+	registry[name] = app
+	return nil
+}
+
+// Lookup returns the app registered under name.
+func Lookup(name string) (MapReduceApp, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	app, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown app %q (available: %v)", name, names())
+	}
+	return app, nil
+}
+
+// New returns the app registered under name, configured with params.
+func New(name string, params map[string]string) (MapReduceApp, error) {
+	app, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := app.(Configurable); ok {
+		return c.Configure(params)
+	}
+	if len(params) > 0 {
+		return nil, fmt.Errorf("app %q takes no parameters", name)
+	}
+	return app, nil
+}
+
+// Names returns the registered app names in sorted order.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return names()
+}
+
+func names() []string {
+	list := make([]string, 0, len(registry))
+	for name := range registry {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
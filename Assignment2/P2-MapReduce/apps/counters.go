@@ -0,0 +1,46 @@
+package apps
+
+// Names of the counters kept by the workers for every task. The master adds
+// up the counters of the kept attempt of every task into the job's counters.
+const (
+	MapInputRecords      = "map.input_records"      // records read by the input format
+	MapOutputRecords     = "map.output_records"     // pairs emitted by the map function
+	MapSpilledRecords    = "map.spilled_records"    // pairs written to disk: spilled runs, merge passes and intermediate files
+	MapPartitionBytes    = "map.partition_bytes."   // + reduce task ID: intermediate bytes written for that reducer
+	CombineInputRecords  = "combine.input_records"  // pairs passed to the combiner
+	CombineOutputRecords = "combine.output_records" // pairs the combiner returned
+	ReduceInputGroups    = "reduce.input_groups"    // distinct keys passed to the reduce function
+	ReduceInputRecords   = "reduce.input_records"   // intermediate pairs read, before any combining
+	ReduceOutputRecords  = "reduce.output_records"  // pairs written to the output
+	ReduceSpilledRecords = "reduce.spilled_records" // pairs written to disk by merge passes, see worker.Worker.TaskMemory
+	AppCounterPrefix     = "app."                   // + name: counters of the app itself
+)
+
+// Counters are named counts kept while a task runs. They are not safe for
+// concurrent use; every task has its own.
+type Counters map[string]int64
+
+// Add adds n to the counter with the given name.
+func (c Counters) Add(name string, n int64) {
+	c[name] += n
+}
+
+// AddAll adds every counter of other under prefix + its name.
+func (c Counters) AddAll(prefix string, other Counters) {
+	for name, n := range other {
+		c[prefix+name] += n
+	}
+}
+
+// CountingMapper is implemented by apps that count things of their own in
+// the map function, such as malformed records. The worker calls
+// MapWithCounters instead of Map; the counters are reported under
+// AppCounterPrefix + name.
+type CountingMapper interface {
+	MapWithCounters(filename string, contents string, counters Counters) []KeyValue
+}
+
+// CountingReducer is the reduce side of CountingMapper.
+type CountingReducer interface {
+	ReduceWithCounters(key string, values []string, counters Counters) string
+}
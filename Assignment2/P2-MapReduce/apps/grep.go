@@ -0,0 +1,106 @@
+package apps
+
+import (
+	"fmt"
+	"iter"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Grep outputs every line that matches a regular expression, keyed by
+// file:line, e.g. "dataset/file1.txt:3 the matching line".
+//
+// Parameters:
+//
+//	pattern=RE        regular expression in Go (RE2) syntax; required
+//	ignore_case=true  match without regard to case
+//
+// It relies on the whole_file input format, so that each call to Map sees a
+// whole file and line numbers count from its start; jobs naming another
+// format are rejected. Intermediate keys hold the file and the
+// zero-padded line number, so the lines of a file come out in line order
+// rather than with line 10 before line 9; the reducer writes them as
+// file:line.
+type Grep struct {
+	re *regexp.Regexp
+}
+
+func init() {
+	Register("grep", Grep{})
+}
+
+func (g Grep) Configure(params map[string]string) (MapReduceApp, error) {
+	pattern, ignoreCase := "", false
+	for name, value := range params {
+		switch name {
+		case "pattern":
+			pattern = value
+		case "ignore_case":
+			on, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("grep: ignore_case must be true or false, got %q", value)
+			}
+			ignoreCase = on
+		default:
+			return nil, fmt.Errorf("grep: unknown parameter %q (available: pattern, ignore_case)", name)
+		}
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("grep: the pattern parameter is required")
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("grep: bad pattern: %v", err)
+	}
+	g.re = re
+	return g, nil
+}
+
+func (Grep) InputFormat() string { return "whole_file" }
+
+// Map emits the key of file and line number and the line for every matching
+// line.
+func (g Grep) Map(filename string, contents string) []KeyValue {
+	var kvs []KeyValue
+	lines := strings.Split(contents, "\n")
+	if n := len(lines); lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if g.re.MatchString(line) {
+			kvs = append(kvs, KeyValue{Key: fmt.Sprintf("%s\x00%010d", filename, i+1), Value: line})
+		}
+	}
+	return kvs
+}
+
+// ReduceStream writes the first value of a key, the matching line, under
+// file:line without reading the others. A key has more than one value only
+// if the same file was read twice, and then they are all the same line.
+func (Grep) ReduceStream(key string, values iter.Seq[string], emit func(KeyValue)) {
+	for line := range values {
+		emit(KeyValue{Key: location(key), Value: line})
+		return
+	}
+}
+
+// Reduce returns the line ReduceStream writes for a key. The worker calls
+// ReduceStream instead.
+func (g Grep) Reduce(key string, values []string) string {
+	var line string
+	g.ReduceStream(key, slices.Values(values), func(kv KeyValue) { line = kv.Value })
+	return line
+}
+
+// location formats a key of Map as file:line.
+func location(key string) string {
+	file, n, _ := strings.Cut(key, "\x00")
+	line, _ := strconv.Atoi(n)
+	return fmt.Sprintf("%s:%d", file, line)
+}
@@ -0,0 +1,51 @@
+package apps
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InvertedIndex maps each word to the list of files it appears in.
+type InvertedIndex struct{}
+
+func init() {
+	Register("inverted_index", InvertedIndex{})
+}
+
+func (InvertedIndex) Map(filename string, contents string) []KeyValue {
+	var kvs []KeyValue
+	for _, word := range strings.Fields(contents) {
+		kvs = append(kvs, KeyValue{Key: word, Value: filepath.Base(filename)})
+	}
+	return kvs
+}
+
+// Reduce returns the sorted file names as a JSON array, e.g.
+// ["file1.txt","file2.txt"], so file names with spaces stay unambiguous.
+func (InvertedIndex) Reduce(key string, values []string) string {
+	list, _ := json.Marshal(distinct(values))
+	return string(list)
+}
+
+func (InvertedIndex) JSONValues() bool { return true }
+
+// Combine drops repeated occurrences of a word in the same file.
+func (InvertedIndex) Combine(key string, values []string) []string {
+	return distinct(values)
+}
+
+// distinct returns the unique values in sorted order.
+func distinct(values []string) []string {
+	seen := make(map[string]bool)
+	unique := []string{}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
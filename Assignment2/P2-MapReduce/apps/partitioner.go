@@ -0,0 +1,83 @@
+package apps
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+// Names of the built-in partitioners, as given in a job spec.
+const (
+	HashPartitioning  = "hash"
+	RangePartitioning = "range"
+)
+
+// MaxReducers is the largest number of reduce partitions a job may have.
+// Every map task writes a file for each of them.
+const MaxReducers = 1000
+
+// Partitioner assigns an intermediate key to one of numReducers reduce
+// partitions. It must return the same partition for the same key in every
+// map task of a job.
+type Partitioner interface {
+	Partition(key string, numReducers int) int
+}
+
+// HashPartitioner spreads keys evenly over the reducers with a 32-bit FNV-1a
+// hash. Each reducer's output is sorted, but the outputs are not ordered
+// relative to each other.
+type HashPartitioner struct{}
+
+func (HashPartitioner) Partition(key string, numReducers int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(numReducers))
+}
+
+// RangePartitioner sends keys up to and including Boundaries[i] to partition
+// i, and keys above the last boundary to the last partition, so that the
+// concatenated reducer outputs are totally ordered. It needs numReducers-1
+// sorted boundaries, usually picked with SampleBoundaries.
+type RangePartitioner struct {
+	Boundaries []string
+}
+
+func (p RangePartitioner) Partition(key string, numReducers int) int {
+	i := sort.SearchStrings(p.Boundaries, key)
+	if i >= numReducers {
+		i = numReducers - 1
+	}
+	return i
+}
+
+// NewPartitioner returns the partitioner named in a job spec. An empty name
+// selects hash partitioning.
+func NewPartitioner(name string, boundaries []string) (Partitioner, error) {
+	switch name {
+	case "", HashPartitioning:
+		return HashPartitioner{}, nil
+	case RangePartitioning:
+		return RangePartitioner{Boundaries: boundaries}, nil
+	}
+	return nil, fmt.Errorf("unknown partitioner %q (available: %s, %s)", name, HashPartitioning, RangePartitioning)
+}
+
+// SampleBoundaries picks numReducers-1 boundaries for a RangePartitioner from
+// a sample of the intermediate keys. Keys are weighted by how often they
+// occur in the sample, so each partition gets about the same number of
+// pairs. It sorts keys in place.
+func SampleBoundaries(keys []string, numReducers int) []string {
+	if len(keys) == 0 || numReducers < 2 {
+		return nil
+	}
+	sort.Strings(keys)
+	boundaries := make([]string, 0, numReducers-1)
+	for i := 1; i < numReducers; i++ {
+		j := i*len(keys)/numReducers - 1
+		if j < 0 {
+			j = 0
+		}
+		boundaries = append(boundaries, keys[j])
+	}
+	return boundaries
+}
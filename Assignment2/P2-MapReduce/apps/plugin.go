@@ -0,0 +1,64 @@
+package apps
+
+import (
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"strings"
+)
+
+// LoadPlugin opens a Go plugin built with -buildmode=plugin and registers the
+// app it exports. The plugin must export a variable App implementing
+// MapReduceApp, and may export a string Name; otherwise the app is registered
+// under the file name without its .so extension. It fails if an app is
+// already registered under that name.
+func LoadPlugin(path string) (string, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open plugin %s: %v", path, err)
+	}
+	sym, err := p.Lookup("App")
+	if err != nil {
+		return "", fmt.Errorf("plugin %s does not export App: %v", path, err)
+	}
+	// Exported variables are looked up as pointers to the variable.
+	var app MapReduceApp
+	switch v := sym.(type) {
+	case MapReduceApp:
+		app = v
+	case *MapReduceApp:
+		app = *v
+	default:
+		return "", fmt.Errorf("plugin %s: App (%T) does not implement MapReduceApp", path, sym)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), ".so")
+	if sym, err := p.Lookup("Name"); err == nil {
+		if s, ok := sym.(*string); ok && *s != "" {
+			name = *s
+		}
+	}
+	// A name that is taken, by a built-in app or an earlier plugin, is an
+	// error rather than a panic, so a bad -plugins flag is reported.
+	if err := register(name, app); err != nil {
+		return "", fmt.Errorf("plugin %s: app %v", path, err)
+	}
+	return name, nil
+}
+
+// LoadPlugins loads a comma-separated list of plugins with LoadPlugin and
+// returns the names of their apps.
+func LoadPlugins(list string) ([]string, error) {
+	var names []string
+	for _, path := range strings.Split(list, ",") {
+		if path == "" {
+			continue
+		}
+		name, err := LoadPlugin(path)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
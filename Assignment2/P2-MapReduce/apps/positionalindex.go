@@ -0,0 +1,119 @@
+package apps
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// PositionalIndex maps each normalised term to the documents it appears in,
+// with the number of times and the places it appears in each, which is enough
+// to answer phrase queries. Documents are identified by their input path.
+//
+// Parameters:
+//
+//	stopwords=true  leave common English words out of the index
+//	stem=true       index words by their stem, so "jumped" matches "jumps"
+//
+// It relies on the whole_file input format, so that each call to Map sees a
+// whole document and line numbers and positions count from its start; jobs
+// naming another format are rejected.
+type PositionalIndex struct {
+	Tokenizer Tokenizer
+}
+
+// Posting is one document's entry in the postings list of a term.
+type Posting struct {
+	Doc       string     `json:"doc"`
+	TF        int        `json:"tf"` // number of occurrences in the document
+	Positions []Position `json:"positions"`
+}
+
+// Position is where one occurrence of a term is in a document.
+type Position struct {
+	Line int `json:"line"` // 1-based line number
+	Pos  int `json:"pos"`  // 0-based word index in the document
+}
+
+// PostingsList is the index entry of one term, the value of each output pair.
+type PostingsList struct {
+	DF       int       `json:"df"` // number of documents containing the term
+	Postings []Posting `json:"postings"`
+}
+
+func init() {
+	Register("positional_index", PositionalIndex{})
+}
+
+func (p PositionalIndex) Configure(params map[string]string) (MapReduceApp, error) {
+	for name, value := range params {
+		on, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("positional_index: %s must be true or false, got %q", name, value)
+		}
+		switch name {
+		case "stopwords":
+			p.Tokenizer.StopWords = on
+		case "stem":
+			p.Tokenizer.Stem = on
+		default:
+			return nil, fmt.Errorf("positional_index: unknown parameter %q (available: stopwords, stem)", name)
+		}
+	}
+	return p, nil
+}
+
+func (PositionalIndex) InputFormat() string { return "whole_file" }
+
+// Map emits one posting per distinct term of the document.
+func (p PositionalIndex) Map(filename string, contents string) []KeyValue {
+	postings := make(map[string]*Posting)
+	var terms []string
+	for _, tok := range p.Tokenizer.Tokenize(contents) {
+		posting, ok := postings[tok.Term]
+		if !ok {
+			posting = &Posting{Doc: filename}
+			postings[tok.Term] = posting
+			terms = append(terms, tok.Term)
+		}
+		posting.TF++
+		posting.Positions = append(posting.Positions, Position{Line: tok.Line, Pos: tok.Pos})
+	}
+	kvs := make([]KeyValue, 0, len(terms))
+	for _, term := range terms {
+		value, _ := json.Marshal(postings[term])
+		kvs = append(kvs, KeyValue{Key: term, Value: string(value)})
+	}
+	return kvs
+}
+
+// Reduce merges the postings of a term into a PostingsList sorted by
+// document. Postings of the same document, which only occur if it was read
+// in several records, are merged into one.
+func (PositionalIndex) Reduce(key string, values []string) string {
+	byDoc := make(map[string]*Posting)
+	for _, v := range values {
+		var p Posting
+		if err := json.Unmarshal([]byte(v), &p); err != nil {
+			continue
+		}
+		if merged, ok := byDoc[p.Doc]; ok {
+			merged.TF += p.TF
+			merged.Positions = append(merged.Positions, p.Positions...)
+		} else {
+			byDoc[p.Doc] = &p
+		}
+	}
+	list := PostingsList{Postings: make([]Posting, 0, len(byDoc))}
+	for _, p := range byDoc {
+		sort.Slice(p.Positions, func(i, j int) bool { return p.Positions[i].Pos < p.Positions[j].Pos })
+		list.Postings = append(list.Postings, *p)
+	}
+	sort.Slice(list.Postings, func(i, j int) bool { return list.Postings[i].Doc < list.Postings[j].Doc })
+	list.DF = len(list.Postings)
+	out, _ := json.Marshal(list)
+	return string(out)
+}
+
+func (PositionalIndex) JSONValues() bool { return true }
@@ -0,0 +1,85 @@
+package apps
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Sort sorts the lines of its input, TeraSort style: the master samples the
+// sort keys to pick the boundaries of a range partitioner, every reducer
+// sorts one key range, and the reducer outputs out-0, out-1, ... concatenated
+// are the sorted input. Empty lines are dropped; duplicate lines are kept.
+//
+// Parameters:
+//
+//	field=N  sort by the Nth whitespace-separated field (from 1) rather than
+//	         the whole line; lines without it sort first, and lines with
+//	         equal fields are ordered by the whole line
+type Sort struct {
+	Field int
+}
+
+func init() {
+	Register("sort", Sort{})
+}
+
+func (s Sort) Configure(params map[string]string) (MapReduceApp, error) {
+	for name, value := range params {
+		if name != "field" {
+			return nil, fmt.Errorf("sort: unknown parameter %q (available: field)", name)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("sort: field must be a positive number, got %q", value)
+		}
+		s.Field = n
+	}
+	return s, nil
+}
+
+func (Sort) Partitioning() string { return RangePartitioning }
+
+// Map emits the sort key of every line with the line as the value. With a
+// field, the key is the field followed by the whole line, so lines with equal
+// fields are ordered by the merge rather than held and sorted by the reducer.
+func (s Sort) Map(filename string, contents string) []KeyValue {
+	var kvs []KeyValue
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		kvs = append(kvs, KeyValue{Key: s.key(line), Value: line})
+	}
+	return kvs
+}
+
+func (s Sort) key(line string) string {
+	if s.Field == 0 {
+		return line
+	}
+	fields := strings.Fields(line)
+	if s.Field > len(fields) {
+		return "\x00" + line
+	}
+	return fields[s.Field-1] + "\x00" + line
+}
+
+// ReduceStream writes the lines of a key as keys with empty values. They are
+// all the same line, since the key holds the whole line.
+func (Sort) ReduceStream(key string, values iter.Seq[string], emit func(KeyValue)) {
+	for line := range values {
+		emit(KeyValue{Key: line})
+	}
+}
+
+// Reduce returns the lines ReduceStream writes for a key, one per line. The
+// worker calls ReduceStream instead.
+func (s Sort) Reduce(key string, values []string) string {
+	var lines []string
+	s.ReduceStream(key, slices.Values(values), func(kv KeyValue) { lines = append(lines, kv.Key) })
+	return strings.Join(lines, "\n")
+}
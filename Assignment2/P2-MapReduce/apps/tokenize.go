@@ -0,0 +1,117 @@
+package apps
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token is one normalised word of a text.
+type Token struct {
+	Term string // normalised form of the word
+	Pos  int    // index of the word in the text, counting stop words
+	Line int    // 1-based line the word is on
+}
+
+// Tokenizer splits text into normalised words: runs of letters and digits,
+// lowercased, so "Fox." and "fox" are the same term. Apostrophes inside a
+// word are dropped ("don't" becomes "dont"). Stop words are left out if
+// StopWords is set, but still count for the positions of the words after
+// them, and words are reduced to their stem if Stem is set.
+type Tokenizer struct {
+	StopWords bool
+	Stem      bool
+}
+
+// Tokenize returns the tokens of text in order.
+func (t Tokenizer) Tokenize(text string) []Token {
+	var tokens []Token
+	var word strings.Builder
+	pos, line := 0, 1
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		term := word.String()
+		word.Reset()
+		if !t.StopWords || !stopWords[term] {
+			if t.Stem {
+				term = stem(term)
+			}
+			tokens = append(tokens, Token{Term: term, Pos: pos, Line: line})
+		}
+		pos++
+	}
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(unicode.ToLower(r))
+		case (r == '\'' || r == '’') && word.Len() > 0 && i+1 < len(runes) && unicode.IsLetter(runes[i+1]):
+			// An apostrophe inside a word.
+		default:
+			flush()
+			if r == '\n' {
+				line++
+			}
+		}
+	}
+	flush()
+	return tokens
+}
+
+// stopWords are common English words that carry little meaning on their own.
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`a about above after again against all am an and any are as at be
+		because been before being below between both but by can could did do does doing down during
+		each few for from further had has have having he her here hers herself him himself his how i
+		if in into is it its itself just me more most my myself no nor not now of off on once only or
+		other our ours ourselves out over own same she should so some such than that the their theirs
+		them themselves then there these they this those through to too under until up very was we
+		were what when where which while who whom why will with would you your yours yourself yourselves`) {
+		stopWords[w] = true
+	}
+}
+
+// stem strips common English inflectional suffixes, a light variant of the
+// first step of the Porter stemmer: plurals ("foxes", "dogs"), past tenses
+// ("jumped") and gerunds ("jumping"), plus "-ly" adverbs. It only has to be
+// consistent, not produce dictionary words: "jumping" and "jumped" both
+// become "jump", "running" becomes "run".
+func stem(w string) string {
+	if len(w) <= 3 {
+		return w
+	}
+	switch {
+	case strings.HasSuffix(w, "sses"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(w, "ies") && len(w) > 4:
+		return w[:len(w)-3] + "y"
+	case strings.HasSuffix(w, "ss") || strings.HasSuffix(w, "us") || strings.HasSuffix(w, "is"):
+		// "glass", "cactus", "this" are not plurals.
+	case strings.HasSuffix(w, "xes") || strings.HasSuffix(w, "ches") || strings.HasSuffix(w, "shes"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(w, "s"):
+		return w[:len(w)-1]
+	}
+	for _, suffix := range []string{"ing", "ed"} {
+		base, ok := strings.CutSuffix(w, suffix)
+		if !ok || !hasVowel(base) || len(base) < 2 {
+			continue
+		}
+		// "running" -> "run", but "falling" stays "fall".
+		if n := len(base); base[n-1] == base[n-2] && !strings.ContainsRune("lsz", rune(base[n-1])) {
+			base = base[:n-1]
+		}
+		return base
+	}
+	if base, ok := strings.CutSuffix(w, "ly"); ok && len(base) > 2 {
+		return base
+	}
+	return w
+}
+
+func hasVowel(s string) bool {
+	return strings.ContainsAny(s, "aeiouy")
+}
@@ -0,0 +1,109 @@
+package apps
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TopK picks the k keys with the largest counts from "key count" lines, such
+// as the output of word_count. Every pair goes to a single key, "top", whose
+// value is a JSON array of {"key","count"} objects, largest count first and
+// ties in key order. The combiner keeps only the top k of each map task, so
+// the one reducer sees at most k values per map task.
+//
+// Parameters:
+//
+//	k=N  number of keys to keep (default 10)
+type TopK struct {
+	K int
+}
+
+type counted struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+func init() {
+	Register("top_k", TopK{K: 10})
+}
+
+func (t TopK) Configure(params map[string]string) (MapReduceApp, error) {
+	for name, value := range params {
+		if name != "k" {
+			return nil, fmt.Errorf("top_k: unknown parameter %q (available: k)", name)
+		}
+		k, err := strconv.Atoi(value)
+		if err != nil || k < 1 {
+			return nil, fmt.Errorf("top_k: k must be a positive number, got %q", value)
+		}
+		t.K = k
+	}
+	return t, nil
+}
+
+func (t TopK) Map(filename string, contents string) []KeyValue {
+	return t.MapWithCounters(filename, contents, Counters{})
+}
+
+// MapWithCounters emits "count key" for a "key count" line. Lines without a
+// count are skipped and counted as skipped_lines.
+func (TopK) MapWithCounters(filename string, contents string, counters Counters) []KeyValue {
+	var kvs []KeyValue
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		if i < 0 {
+			counters.Add("skipped_lines", 1)
+			continue
+		}
+		count, err := strconv.ParseInt(line[i+1:], 10, 64)
+		if err != nil {
+			counters.Add("skipped_lines", 1)
+			continue
+		}
+		kvs = append(kvs, KeyValue{Key: "top", Value: fmt.Sprintf("%d %s", count, line[:i])})
+	}
+	return kvs
+}
+
+func (t TopK) Reduce(key string, values []string) string {
+	out, _ := json.Marshal(t.top(values))
+	return string(out)
+}
+
+func (TopK) JSONValues() bool { return true }
+
+func (t TopK) Combine(key string, values []string) []string {
+	top := t.top(values)
+	combined := make([]string, len(top))
+	for i, c := range top {
+		combined[i] = fmt.Sprintf("%d %s", c.Count, c.Key)
+	}
+	return combined
+}
+
+// top parses "count key" values and returns the k largest.
+func (t TopK) top(values []string) []counted {
+	list := make([]counted, 0, len(values))
+	for _, v := range values {
+		n, key, _ := strings.Cut(v, " ")
+		count, _ := strconv.ParseInt(n, 10, 64)
+		list = append(list, counted{Key: key, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Key < list[j].Key
+	})
+	if len(list) > t.K {
+		list = list[:t.K]
+	}
+	return list
+}
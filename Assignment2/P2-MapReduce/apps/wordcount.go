@@ -0,0 +1,37 @@
+package apps
+
+import (
+	"strconv"
+	"strings"
+)
+
+// WordCount counts the occurrences of each word across all input files.
+type WordCount struct{}
+
+func init() {
+	Register("word_count", WordCount{})
+}
+
+func (WordCount) Map(filename string, contents string) []KeyValue {
+	var kvs []KeyValue
+	for _, word := range strings.Fields(contents) {
+		kvs = append(kvs, KeyValue{Key: word, Value: "1"})
+	}
+	return kvs
+}
+
+func (WordCount) Reduce(key string, values []string) string {
+	total := 0
+	for _, v := range values {
+		n, _ := strconv.Atoi(v)
+		total += n
+	}
+	return strconv.Itoa(total)
+}
+
+func (WordCount) JSONValues() bool { return true }
+
+// Combine sums the counts from one map task.
+func (wc WordCount) Combine(key string, values []string) []string {
+	return []string{wc.Reduce(key, values)}
+}
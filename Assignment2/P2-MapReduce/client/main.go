@@ -1,86 +1,33 @@
 package main
 
 import (
-	"context"
+	"flag"
 	"fmt"
 	"net"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strconv"
-	"sync"
 	"time"
 
+	"github.com/example/apps"
+	"github.com/example/master"
 	pb "github.com/example/protofiles"
+	"github.com/example/security"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
-// Master struct
-type Master struct {
-	pb.UnimplementedMasterServer
-	numMappers  int
-	numReducers int
-	Mode        string // Add this
-}
-
-// AssignMapTask starts a mapper and assigns a map task
-func (m *Master) AssignMapTask(ctx context.Context, req *pb.MapRequest) (*pb.TaskResponse, error) {
-	fmt.Printf("Assigning map task %d for file %s\n", req.MapTaskId, req.Filename)
-
-	port := 56052 + int(req.MapTaskId)
-	m.startWorker(port)
-
-	conn, err := grpc.Dial(fmt.Sprintf(":%d", port), grpc.WithInsecure())
-	if err != nil {
-		return nil, err
-	}
-	defer conn.Close()
-
-	client := pb.NewWorkerClient(conn)
-	return client.Map(ctx, req)
-}
-
-// AssignReduceTask starts a reducer and assigns a reduce task
-func (m *Master) AssignReduceTask(ctx context.Context, req *pb.ReduceRequest) (*pb.TaskResponse, error) {
-	fmt.Printf("Assigning reduce task %d\n", req.ReduceTaskId)
-
-	port := 56052 + m.numMappers + int(req.ReduceTaskId)
-	m.startWorker(port)
-
-	conn, err := grpc.Dial(fmt.Sprintf(":%d", port), grpc.WithInsecure())
-	if err != nil {
-		return nil, err
-	}
-	defer conn.Close()
-
-	client := pb.NewWorkerClient(conn)
-	return client.Reduce(ctx, req)
-}
-
-// startWorker launches a worker on the given port
-func (m *Master) startWorker(port int) {
-	cmd := exec.Command("go", "run", "server/main.go", fmt.Sprintf("%d", port))
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-
-	err := cmd.Start()
-	if err != nil {
-		fmt.Printf("Failed to start worker on port %d: %v\n", port, err)
-	}
-
-	time.Sleep(1 * time.Second)
-}
+const masterAddr = ":50051"
 
 // startMaster starts the master server
-func startMaster(numMappers, numReducers int, mode string) {
-	lis, err := net.Listen("tcp", ":50051")
+func startMaster(m *master.Master, creds credentials.TransportCredentials) {
+	lis, err := net.Listen("tcp", masterAddr)
 	if err != nil {
 		fmt.Printf("Failed to listen: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
-	grpcServer := grpc.NewServer()
-	pb.RegisterMasterServer(grpcServer, &Master{numMappers: numMappers, numReducers: numReducers, Mode: mode})
+	grpcServer := grpc.NewServer(grpc.Creds(creds))
+	pb.RegisterMasterServer(grpcServer, m)
 
 	fmt.Println("Master server listening on :50051")
 	if err := grpcServer.Serve(lis); err != nil {
@@ -89,81 +36,85 @@ func startMaster(numMappers, numReducers int, mode string) {
 }
 
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: go run client/main.go <numReducers> <mode>")
+	numWorkers := flag.Int("workers", 3, "Number of worker processes to start (0 to use externally started workers)")
+	splitSize := flag.Int64("split-size", master.DefaultSplitSize, "Maximum input split size in bytes (splits are extended to the end of a line)")
+	speculative := flag.Bool("speculative", true, "Launch backup attempts for straggler tasks near the end of each phase")
+	plugins := flag.String("plugin", "", "Comma-separated list of MapReduce app plugins (.so) for the master and workers to load")
+	partitioner := flag.String("partitioner", "", "Partitioner: hash, or range for totally ordered output across reducers (default: the app's, or hash)")
+	outputFormat := flag.String("output-format", "text", "Output format: text, csv or json_lines, optionally with +gzip, +zstd or +snappy")
+	intermediateCodec := flag.String("intermediate-codec", "", "Codec of the intermediate files: none, gzip, zstd or snappy (default none)")
+	httpAddr := flag.String("http", "localhost:8080", "Address for the status dashboard and JSON API, which is unauthenticated plain HTTP (\"\" to disable, \":8080\" for every interface)")
+	inputRoot := flag.String("input-root", ".", "Directory the input files must be in (\"\" to allow any file); also passed to the workers")
+	token := flag.String("token", os.Getenv(security.TokenEnv), "Token workers must register with (default $"+security.TokenEnv+", \"\" to accept any worker)")
+	tlsFlags := security.AddFlags()
+	params := apps.Params{}
+	flag.Var(params, "param", "App parameter as key=value; repeat for several")
+	flag.Parse()
+	if flag.NArg() < 2 {
+		fmt.Println("Usage: go run client/main.go [-workers N] [-split-size bytes] [-speculative=false] [-plugin app.so] [-partitioner hash|range] [-output-format name] [-param key=value]... [-http addr] [-input-root dir] [-token t] [-tls-ca ca.pem -tls-cert cert.pem -tls-key key.pem] <numReducers> <mode>")
+		fmt.Printf("Built-in modes: %v\n", apps.Names())
 		return
 	}
 
-	numReducers, _ := strconv.Atoi(os.Args[1])
-	mode := os.Args[2]
-	inputDir := "dataset"
-	files, err := filepath.Glob(filepath.Join(inputDir, "*.txt"))
+	numReducers, _ := strconv.Atoi(flag.Arg(0))
+	mode := flag.Arg(1)
+	// The master loads plugins too, to sample keys for range partitioning.
+	if _, err := apps.LoadPlugins(*plugins); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if _, err := apps.New(mode, params); err != nil {
+		fmt.Println(err)
+		return
+	}
+	creds, err := tlsFlags.Credentials()
 	if err != nil {
-		fmt.Printf("Failed to read input directory: %v\n", err)
+		fmt.Printf("Failed to set up TLS: %v\n", err)
 		return
 	}
 
-	numMappers := len(files)
-	if numMappers == 0 {
-		fmt.Println("No input files found")
+	// The client runs a master for a single job over dataset/*.txt. Use
+	// master_server and mrctl to run several jobs on a long-running master.
+	m := master.New()
+	m.Speculative = *speculative
+	m.WorkerToken = *token
+	m.InputRoot = *inputRoot
+	job, err := m.Submit(&pb.JobSpec{
+		InputGlob:         "dataset/*.txt",
+		App:               mode,
+		NumReducers:       int32(numReducers),
+		OutputDir:         "output",
+		SplitSize:         *splitSize,
+		Partitioner:       *partitioner,
+		OutputFormat:      *outputFormat,
+		IntermediateCodec: *intermediateCodec,
+		Params:            params,
+	})
+	if err != nil {
+		fmt.Println(err)
 		return
 	}
-
-	go startMaster(numMappers, numReducers, mode)
-
-	time.Sleep(2 * time.Second) // Let master start up
-
-	var wg sync.WaitGroup
-
-	// Start mappers
-	for i, file := range files {
-		wg.Add(1)
-		go func(i int, file string) {
-			defer wg.Done()
-			conn, err := grpc.Dial(":50051", grpc.WithInsecure())
-			if err != nil {
-				fmt.Printf("Failed to connect to master: %v\n", err)
-				return
-			}
-			defer conn.Close()
-
-			client := pb.NewMasterClient(conn)
-			req := &pb.MapRequest{MapTaskId: int32(i), Filename: file, NumReducers: int32(numReducers), Mode: mode}
-			res, err := client.AssignMapTask(context.Background(), req)
-			if err != nil {
-				fmt.Printf("Map task failed: %v\n", err)
-				return
-			}
-			fmt.Println(res.Message)
-		}(i, file)
-		time.Sleep(1 * time.Second)
+	if *httpAddr != "" {
+		go master.ServeDashboard(*httpAddr, m)
 	}
-
-	wg.Wait()
-
-	// Start reducers
-	for i := 0; i < numReducers; i++ {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-			conn, err := grpc.Dial(":50051", grpc.WithInsecure())
-			if err != nil {
-				fmt.Printf("Failed to connect to master: %v\n", err)
-				return
-			}
-			defer conn.Close()
-
-			client := pb.NewMasterClient(conn)
-			req := &pb.ReduceRequest{ReduceTaskId: int32(i), Mode: mode}
-			res, err := client.AssignReduceTask(context.Background(), req)
-			if err != nil {
-				fmt.Printf("Reduce task failed: %v\n", err)
-				return
-			}
-			fmt.Println(res.Message)
-		}(i)
-		time.Sleep(1 * time.Second)
+	go startMaster(m, creds)
+	go m.Run()
+	// The workers inherit the token through the environment.
+	os.Setenv(security.TokenEnv, *token)
+	workers := master.StartWorkers(*numWorkers, "localhost"+masterAddr, *plugins, append(tlsFlags.Args(), "-input-root", *inputRoot)...)
+
+	// Workers pull tasks with RequestTask. The master re-assigns tasks whose
+	// worker fails or stops heartbeating, and starts reducers only after all
+	// maps completed.
+	err = job.Wait()
+	m.Shutdown()
+	master.WaitWorkers(workers, 10*time.Second)
+	// Keep serving briefly so externally started workers also abort their
+	// backup attempts and are told to exit.
+	time.Sleep(2 * time.Second)
+	if err != nil {
+		fmt.Printf("Job failed: %v\n", err)
+		os.Exit(1)
 	}
-
-	wg.Wait()
-}
\ No newline at end of file
+	fmt.Println("Job completed")
+}
@@ -0,0 +1,133 @@
+package formats
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Names of the codecs. An input or output format name can end in "+" and a
+// codec name, e.g. "csv+zstd", for files compressed with that codec, and a
+// job's intermediate files can be compressed with any of them.
+const (
+	None   = "none"
+	Gzip   = "gzip"
+	Zstd   = "zstd"
+	Snappy = "snappy"
+)
+
+// Codec compresses whole files.
+type Codec interface {
+	Name() string
+	// Ext is the extension added to the names of compressed files, e.g. ".gz".
+	Ext() string
+	// NewWriter returns a writer that compresses to w. Close must be called
+	// to flush it; it does not close w.
+	NewWriter(w io.Writer) io.WriteCloser
+	// NewReader returns a reader of the data compressed in r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+var codecs = []Codec{noCodec{}, gzipCodec{}, zstdCodec{}, snappyCodec{}}
+
+// LookupCodec returns the codec with the given name. An empty name selects
+// None, which leaves data as it is.
+func LookupCodec(name string) (Codec, error) {
+	if name == "" {
+		return noCodec{}, nil
+	}
+	for _, c := range codecs {
+		if c.Name() == name {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown codec %q (available: %s, %s, %s, %s)", name, None, Gzip, Zstd, Snappy)
+}
+
+// CutCodec splits a format name such as "text+gzip" into the base format and
+// the codec it is compressed with, None if the name has no codec suffix.
+func CutCodec(name string) (string, Codec) {
+	for _, c := range codecs[1:] {
+		if base, ok := strings.CutSuffix(name, "+"+c.Name()); ok {
+			return base, c
+		}
+	}
+	return name, noCodec{}
+}
+
+// CutCodecExt splits a file name such as "out-0.txt.gz" into the name without
+// the codec's extension and the codec, None if the name has no such
+// extension.
+func CutCodecExt(name string) (string, Codec) {
+	for _, c := range codecs[1:] {
+		if base, ok := strings.CutSuffix(name, c.Ext()); ok {
+			return base, c
+		}
+	}
+	return name, noCodec{}
+}
+
+// codecSuffixes lists the codec suffixes of format names, for error messages.
+func codecSuffixes() string {
+	return fmt.Sprintf("+%s, +%s or +%s", Gzip, Zstd, Snappy)
+}
+
+type noCodec struct{}
+
+func (noCodec) Name() string { return None }
+func (noCodec) Ext() string  { return "" }
+
+func (noCodec) NewWriter(w io.Writer) io.WriteCloser { return nopWriteCloser{w} }
+
+func (noCodec) NewReader(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(r), nil }
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+type gzipCodec struct{}
+
+func (gzipCodec) Name() string { return Gzip }
+func (gzipCodec) Ext() string  { return ".gz" }
+
+func (gzipCodec) NewWriter(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+
+// zstdCodec uses a single goroutine and a 1MB window per stream, as a worker
+// writes one stream per reducer and a reducer reads one per map task at once.
+type zstdCodec struct{}
+
+func (zstdCodec) Name() string { return Zstd }
+func (zstdCodec) Ext() string  { return ".zst" }
+
+func (zstdCodec) NewWriter(w io.Writer) io.WriteCloser {
+	// NewWriter only fails on invalid options.
+	zw, _ := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1), zstd.WithWindowSize(1<<20))
+	return zw
+}
+
+func (zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+	if err != nil {
+		return nil, err
+	}
+	return zr.IOReadCloser(), nil
+}
+
+// snappyCodec writes the Snappy framing format, readable by any Snappy
+// implementation that supports streams.
+type snappyCodec struct{}
+
+func (snappyCodec) Name() string { return Snappy }
+func (snappyCodec) Ext() string  { return ".sz" }
+
+func (snappyCodec) NewWriter(w io.Writer) io.WriteCloser { return snappy.NewBufferedWriter(w) }
+
+func (snappyCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(snappy.NewReader(r)), nil
+}
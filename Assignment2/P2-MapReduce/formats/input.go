@@ -0,0 +1,171 @@
+// Package formats reads the records of a job's input and writes the key/value
+// pairs of its output in one of several file formats.
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Names of the built-in formats. Any of them can be suffixed with a codec
+// for compressed files, e.g. "csv+gzip"; see CutCodec.
+const (
+	Text      = "text"
+	WholeFile = "whole_file"
+	CSV       = "csv"
+	JSONLines = "json_lines"
+)
+
+// InputFormat turns the bytes of an input split into the records passed to
+// the map function, one call per record.
+type InputFormat interface {
+	// Splittable reports whether a file can be cut into splits that start
+	// at the beginning of a line. Files in other formats are read whole by
+	// a single map task.
+	Splittable() bool
+	// ReadRecords calls emit with every record in r, and stops at the first
+	// error emit returns.
+	ReadRecords(r io.Reader, emit func(record string) error) error
+}
+
+// LookupInput returns the input format with the given name. An empty name
+// selects Text.
+func LookupInput(name string) (InputFormat, error) {
+	if base, codec := CutCodec(name); codec.Name() != None {
+		f, err := LookupInput(base)
+		if err != nil {
+			return nil, err
+		}
+		return compressedInput{f, codec}, nil
+	}
+	switch name {
+	case "", Text:
+		return TextInput{}, nil
+	case WholeFile:
+		return WholeFileInput{}, nil
+	case CSV:
+		return CSVInput{}, nil
+	case JSONLines:
+		return JSONLinesInput{}, nil
+	}
+	return nil, fmt.Errorf("unknown input format %q (available: %s, %s, %s, %s, each optionally with %s)",
+		name, Text, WholeFile, CSV, JSONLines, codecSuffixes())
+}
+
+// TextInput reads one record per line, without the line terminator. Lines
+// can be of any length.
+type TextInput struct{}
+
+func (TextInput) Splittable() bool { return true }
+
+func (TextInput) ReadRecords(r io.Reader, emit func(string) error) error {
+	return readLines(r, func(line []byte) error {
+		return emit(string(line))
+	})
+}
+
+// WholeFileInput reads a whole file as a single record.
+type WholeFileInput struct{}
+
+func (WholeFileInput) Splittable() bool { return false }
+
+func (WholeFileInput) ReadRecords(r io.Reader, emit func(string) error) error {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return emit(string(contents))
+}
+
+// CSVInput reads one record per CSV row. Rows are passed on as a single line
+// of CSV, so quoted fields may span lines in the input; that is also why CSV
+// files are not split.
+type CSVInput struct{}
+
+func (CSVInput) Splittable() bool { return false }
+
+func (CSVInput) ReadRecords(r io.Reader, emit func(string) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	var buf bytes.Buffer
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		buf.Reset()
+		w := csv.NewWriter(&buf)
+		w.Write(fields)
+		w.Flush()
+		if err := emit(strings.TrimSuffix(buf.String(), "\n")); err != nil {
+			return err
+		}
+	}
+}
+
+// JSONLinesInput reads one JSON value per line. Blank lines are skipped, and
+// a line that is not valid JSON is an error.
+type JSONLinesInput struct{}
+
+func (JSONLinesInput) Splittable() bool { return true }
+
+func (JSONLinesInput) ReadRecords(r io.Reader, emit func(string) error) error {
+	n := 0
+	return readLines(r, func(line []byte) error {
+		n++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			return nil
+		}
+		if !json.Valid(line) {
+			return fmt.Errorf("line %d is not valid JSON", n)
+		}
+		return emit(string(line))
+	})
+}
+
+// compressedInput reads compressed files in another format. A compressed
+// file can only be read from its start, so it is never split.
+type compressedInput struct {
+	InputFormat
+	codec Codec
+}
+
+func (compressedInput) Splittable() bool { return false }
+
+func (f compressedInput) ReadRecords(r io.Reader, emit func(string) error) error {
+	zr, err := f.codec.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	return f.InputFormat.ReadRecords(zr, emit)
+}
+
+// readLines calls f with every line of r, without its "\n" or "\r\n". The
+// last line does not need a terminator.
+func readLines(r io.Reader, f func(line []byte) error) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			line = bytes.TrimSuffix(line, []byte("\n"))
+			line = bytes.TrimSuffix(line, []byte("\r"))
+			if err := f(line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
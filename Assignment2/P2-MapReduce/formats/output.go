@@ -0,0 +1,140 @@
+package formats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// OutputFormat writes the key/value pairs a reduce task produces.
+type OutputFormat interface {
+	// Ext is the extension of output files in this format, e.g. ".txt".
+	Ext() string
+	// NewWriter returns a writer of pairs to w. Close must be called to
+	// flush it; it does not close w.
+	NewWriter(w io.Writer) RecordWriter
+}
+
+// RecordWriter writes one key/value pair at a time.
+type RecordWriter interface {
+	Write(key, value string) error
+	Close() error
+}
+
+// LookupOutput returns the output format with the given name. An empty name
+// selects Text.
+func LookupOutput(name string) (OutputFormat, error) {
+	if base, codec := CutCodec(name); codec.Name() != None {
+		f, err := LookupOutput(base)
+		if err != nil {
+			return nil, err
+		}
+		return compressedOutput{f, codec}, nil
+	}
+	switch name {
+	case "", Text:
+		return TextOutput{}, nil
+	case CSV:
+		return CSVOutput{}, nil
+	case JSONLines:
+		return JSONLinesOutput{}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q (available: %s, %s, %s, each optionally with %s)",
+		name, Text, CSV, JSONLines, codecSuffixes())
+}
+
+// TextOutput writes one "key value" line per pair, or just the key if the
+// value is empty.
+type TextOutput struct{}
+
+func (TextOutput) Ext() string { return ".txt" }
+
+func (TextOutput) NewWriter(w io.Writer) RecordWriter { return textWriter{w} }
+
+type textWriter struct{ w io.Writer }
+
+func (t textWriter) Write(key, value string) error {
+	if value == "" {
+		_, err := fmt.Fprintf(t.w, "%s\n", key)
+		return err
+	}
+	_, err := fmt.Fprintf(t.w, "%s %s\n", key, value)
+	return err
+}
+
+func (textWriter) Close() error { return nil }
+
+// CSVOutput writes one "key,value" row per pair, quoting fields as needed.
+type CSVOutput struct{}
+
+func (CSVOutput) Ext() string { return ".csv" }
+
+func (CSVOutput) NewWriter(w io.Writer) RecordWriter { return csvWriter{csv.NewWriter(w)} }
+
+type csvWriter struct{ w *csv.Writer }
+
+func (c csvWriter) Write(key, value string) error { return c.w.Write([]string{key, value}) }
+
+func (c csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// JSONLinesOutput writes one {"key": ..., "value": ...} object per line.
+// Values are written as strings unless JSONValues is set; then they must be
+// JSON, such as a count or the file list of the inverted index, and are
+// embedded as they are. A value that is not valid JSON is written as a string
+// either way.
+type JSONLinesOutput struct {
+	JSONValues bool
+}
+
+func (JSONLinesOutput) Ext() string { return ".jsonl" }
+
+func (f JSONLinesOutput) NewWriter(w io.Writer) RecordWriter {
+	return jsonLinesWriter{json.NewEncoder(w), f.JSONValues}
+}
+
+type jsonLinesWriter struct {
+	enc        *json.Encoder
+	jsonValues bool
+}
+
+func (j jsonLinesWriter) Write(key, value string) error {
+	var v any = value
+	if j.jsonValues && json.Valid([]byte(value)) {
+		v = json.RawMessage(value)
+	}
+	return j.enc.Encode(struct {
+		Key   string `json:"key"`
+		Value any    `json:"value"`
+	}{key, v})
+}
+
+func (jsonLinesWriter) Close() error { return nil }
+
+// compressedOutput compresses the output of another format.
+type compressedOutput struct {
+	OutputFormat
+	codec Codec
+}
+
+func (f compressedOutput) Ext() string { return f.OutputFormat.Ext() + f.codec.Ext() }
+
+func (f compressedOutput) NewWriter(w io.Writer) RecordWriter {
+	zw := f.codec.NewWriter(w)
+	return compressedWriter{f.OutputFormat.NewWriter(zw), zw}
+}
+
+type compressedWriter struct {
+	RecordWriter
+	zw io.WriteCloser
+}
+
+func (g compressedWriter) Close() error {
+	if err := g.RecordWriter.Close(); err != nil {
+		return err
+	}
+	return g.zw.Close()
+}
@@ -3,6 +3,7 @@ module github.com/example
 go 1.23.5
 
 require (
+	github.com/klauspost/compress v1.18.0
 	google.golang.org/grpc v1.72.1
 	google.golang.org/protobuf v1.36.6
 )
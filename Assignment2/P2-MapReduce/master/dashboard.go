@@ -0,0 +1,298 @@
+package master
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/example/protofiles"
+)
+
+// Handler returns the master's HTTP status pages: a dashboard at / that
+// refreshes itself, and a JSON API. ServeDashboard serves it.
+//
+//	GET /api/jobs         every job with its phase progress, newest first
+//	GET /api/jobs/{id}    one job with the state of every task
+//	GET /api/workers      the registered workers
+func (m *Master) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", m.serveDashboard)
+	mux.HandleFunc("GET /api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, m.jobViews(false))
+	})
+	mux.HandleFunc("GET /api/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		j, ok := m.jobs[r.PathValue("id")]
+		var v jobView
+		if ok {
+			v = j.view(true)
+		}
+		m.mu.Unlock()
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown job %q", r.PathValue("id")), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, v)
+	})
+	mux.HandleFunc("GET /api/workers", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, m.workerViews())
+	})
+	return mux
+}
+
+// ServeDashboard serves the master's Handler on addr until it fails, and
+// reports the failure, e.g. an address already in use.
+func ServeDashboard(addr string, m *Master) {
+	fmt.Printf("Dashboard listening on %s\n", addr)
+	if err := http.ListenAndServe(addr, m.Handler()); err != nil {
+		fmt.Printf("Failed to serve the dashboard: %v\n", err)
+	}
+}
+
+type jobView struct {
+	ID        string           `json:"id"`
+	App       string           `json:"app"`
+	State     string           `json:"state"`
+	Pipeline  string           `json:"pipeline,omitempty"`
+	Base      string           `json:"base_job,omitempty"` // job whose output an incremental job reused
+	Error     string           `json:"error,omitempty"`
+	Started   time.Time        `json:"started"`
+	Seconds   float64          `json:"seconds"`
+	OutputDir string           `json:"output_dir"`
+	Map       phaseView        `json:"map"`
+	Reduce    phaseView        `json:"reduce"`
+	Counters  map[string]int64 `json:"counters,omitempty"`
+	Tasks     []taskView       `json:"tasks,omitempty"`
+}
+
+type phaseView struct {
+	Total      int     `json:"total"`
+	Completed  int     `json:"completed"`
+	InProgress int     `json:"in_progress"`
+	Progress   float64 `json:"progress"`
+}
+
+type taskView struct {
+	Type     string   `json:"type"`
+	ID       int      `json:"id"`
+	Input    string   `json:"input,omitempty"`
+	State    string   `json:"state"`
+	Attempts int      `json:"attempts"`
+	Workers  []string `json:"workers,omitempty"` // running attempts, or the one that completed
+	Seconds  float64  `json:"seconds"`           // of the completed attempt, or of the oldest running one
+	Progress float64  `json:"progress"`
+	Peak     int64    `json:"buffered_bytes_peak,omitempty"` // of the completed attempt, estimated, in bytes
+	Ratio    float64  `json:"compression_ratio,omitempty"`   // of the files the completed attempt compressed
+	Reused   string   `json:"reused_from,omitempty"`         // job the output was reused from
+}
+
+type workerView struct {
+	ID             string    `json:"id"`
+	Address        string    `json:"address"`
+	LastHeartbeat  time.Time `json:"last_heartbeat"`
+	SinceHeartbeat float64   `json:"since_heartbeat"` // seconds
+	MapSlots       int       `json:"map_slots"`
+	ReduceSlots    int       `json:"reduce_slots"`
+	Running        []string  `json:"running,omitempty"` // attempts running on the worker
+}
+
+// jobViews returns every job, newest first.
+func (m *Master) jobViews(tasks bool) []jobView {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	views := make([]jobView, 0, len(m.jobOrder))
+	for i := len(m.jobOrder) - 1; i >= 0; i-- {
+		views = append(views, m.jobOrder[i].view(tasks))
+	}
+	return views
+}
+
+// view describes the job, and with tasks the state of each of its tasks.
+// Caller holds m.mu.
+func (j *Job) view(tasks bool) jobView {
+	st := j.status()
+	v := jobView{
+		ID:        j.ID,
+		App:       j.Spec.App,
+		State:     stateName(j.state),
+		Error:     st.Error,
+		Started:   j.started,
+		Seconds:   st.ElapsedSeconds,
+		OutputDir: j.outputDir,
+		Map:       newPhaseView(st.Map),
+		Reduce:    newPhaseView(st.Reduce),
+		Counters:  st.Counters,
+	}
+	if j.pipeline != nil {
+		v.Pipeline = j.pipeline.ID
+	}
+	v.Base = st.BaseJob
+	if tasks {
+		now := time.Now()
+		for _, t := range j.allTasks() {
+			v.Tasks = append(v.Tasks, t.view(now))
+		}
+	}
+	return v
+}
+
+func newPhaseView(p *pb.PhaseProgress) phaseView {
+	return phaseView{Total: int(p.Total), Completed: int(p.Completed), InProgress: int(p.InProgress), Progress: p.Progress}
+}
+
+// view describes the task's state. Caller holds m.mu.
+func (t *task) view(now time.Time) taskView {
+	v := taskView{Type: "reduce", ID: t.id, State: t.state.String(), Attempts: t.attempts}
+	if t.kind == pb.TaskType_MAP_TASK {
+		v.Type = "map"
+		v.Input = fmt.Sprintf("%s [%d, %d)", t.input.file, t.input.offset, t.input.offset+t.input.length)
+	}
+	switch t.state {
+	case Completed:
+		v.Workers = []string{t.worker}
+		v.Seconds = t.seconds
+		v.Peak = t.peak
+		v.Ratio = ratio(t.uncompressed, t.compressed)
+		v.Reused = t.reusedFrom
+		v.Progress = 1
+	case InProgress:
+		for _, n := range sortedAttempts(t) {
+			a := t.running[n]
+			v.Workers = append(v.Workers, fmt.Sprintf("%s (attempt %d)", a.worker, n))
+			v.Seconds = max(v.Seconds, now.Sub(a.started).Seconds())
+			v.Progress = max(v.Progress, a.progress)
+		}
+	}
+	return v
+}
+
+func sortedAttempts(t *task) []int {
+	attempts := make([]int, 0, len(t.running))
+	for n := range t.running {
+		attempts = append(attempts, n)
+	}
+	sort.Ints(attempts)
+	return attempts
+}
+
+// workerViews returns the registered workers in registration order.
+func (m *Master) workerViews() []workerView {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	running := make(map[string][]string)
+	for _, t := range m.runningTasks() {
+		for _, n := range sortedAttempts(t) {
+			id := t.running[n].worker
+			running[id] = append(running[id], fmt.Sprintf("%s attempt %d", t, n))
+		}
+	}
+	views := make([]workerView, 0, len(m.workers))
+	for _, w := range m.workers {
+		views = append(views, workerView{
+			ID:             w.id,
+			Address:        w.address,
+			LastHeartbeat:  w.lastHeartbeat,
+			SinceHeartbeat: now.Sub(w.lastHeartbeat).Seconds(),
+			MapSlots:       w.mapSlots,
+			ReduceSlots:    w.reduceSlots,
+			Running:        running[w.id],
+		})
+	}
+	// IDs are worker-<n>, so shorter IDs were registered earlier.
+	sort.Slice(views, func(i, k int) bool {
+		if len(views[i].ID) != len(views[k].ID) {
+			return len(views[i].ID) < len(views[k].ID)
+		}
+		return views[i].ID < views[k].ID
+	})
+	return views
+}
+
+// stateName turns JOB_RUNNING into running.
+func stateName(s pb.JobState) string {
+	return strings.ToLower(strings.TrimPrefix(s.String(), "JOB_"))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func (m *Master) serveDashboard(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		Now     time.Time
+		Jobs    []jobView
+		Workers []workerView
+	}{time.Now(), m.jobViews(true), m.workerViews()}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboard.Execute(w, data); err != nil {
+		fmt.Printf("Failed to render the dashboard: %v\n", err)
+	}
+}
+
+var dashboard = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return fmt.Sprintf("%.0f%%", 100*f) },
+	"seconds": func(f float64) string { return fmt.Sprintf("%.1fs", f) },
+	"mib":     func(n int64) string { return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20)) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="2">
+<title>MapReduce master</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin: 0.5em 0 1em; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; text-align: left; font-size: 0.9em; }
+.bar { display: inline-block; width: 12em; height: 0.9em; background: #eee; vertical-align: middle; }
+.bar div { height: 100%; background: #4a90d9; }
+.running { color: #1a6; } .succeeded { color: #333; } .failed, .cancelled { color: #c33; }
+.idle { color: #999; } .in-progress { color: #1a6; }
+</style>
+</head>
+<body>
+<h1>MapReduce master</h1>
+<p>{{.Now.Format "2006-01-02 15:04:05"}}, refreshes every 2 seconds. JSON: <a href="/api/jobs">/api/jobs</a>, <a href="/api/workers">/api/workers</a></p>
+
+<h2>Workers</h2>
+{{if .Workers}}
+<table>
+<tr><th>ID</th><th>Address</th><th>Last heartbeat</th><th>Slots (map/reduce)</th><th>Running</th></tr>
+{{range .Workers}}
+<tr><td>{{.ID}}</td><td>{{.Address}}</td><td>{{seconds .SinceHeartbeat}} ago</td><td>{{.MapSlots}}/{{.ReduceSlots}}</td>
+<td>{{range .Running}}{{.}}<br>{{end}}</td></tr>
+{{end}}
+</table>
+{{else}}<p>No workers registered.</p>{{end}}
+
+<h2>Jobs</h2>
+{{if not .Jobs}}<p>No jobs submitted.</p>{{end}}
+{{range .Jobs}}
+<details{{if eq .State "running"}} open{{end}}>
+<summary><b>{{.ID}}</b> {{.App}} <span class="{{.State}}">{{.State}}</span> {{seconds .Seconds}}
+{{if .Pipeline}}({{.Pipeline}}){{end}} {{if .Base}}(incremental on {{.Base}}){{end}}
+&nbsp; map <span class="bar"><div style="width: {{percent .Map.Progress}}"></div></span> {{.Map.Completed}}/{{.Map.Total}}
+&nbsp; reduce <span class="bar"><div style="width: {{percent .Reduce.Progress}}"></div></span> {{.Reduce.Completed}}/{{.Reduce.Total}}
+</summary>
+{{if .Error}}<p class="failed">{{.Error}}</p>{{end}}
+<p>Output: {{.OutputDir}} &middot; <a href="/api/jobs/{{.ID}}">JSON</a></p>
+<table>
+<tr><th>Task</th><th>Input</th><th>State</th><th>Worker</th><th>Attempts</th><th>Duration</th><th>Peak buffered</th><th>Compression</th><th>Progress</th></tr>
+{{range .Tasks}}
+<tr><td>{{.Type}} {{.ID}}</td><td>{{.Input}}</td><td class="{{.State}}">{{.State}}{{if .Reused}} (reused from {{.Reused}}){{end}}</td>
+<td>{{range .Workers}}{{.}}<br>{{end}}</td><td>{{.Attempts}}</td>
+<td>{{if .Seconds}}{{seconds .Seconds}}{{end}}</td><td>{{if .Peak}}{{mib .Peak}}{{end}}</td><td>{{if .Ratio}}{{printf "%.2fx" .Ratio}}{{end}}</td><td>{{percent .Progress}}</td></tr>
+{{end}}
+</table>
+</details>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,201 @@
+package master
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/example/apps"
+	pb "github.com/example/protofiles"
+	"google.golang.org/protobuf/proto"
+)
+
+// An incremental job records the SHA-256 checksum of every input file. When
+// another incremental job with the same spec is submitted, the latest such
+// job that succeeded is its base: every split of a file whose checksum did
+// not change keeps the base's map output, which stays on the worker that
+// wrote it, and only the other splits are mapped again. Once the map phase
+// is over, a reduce partition that no new map output and no dropped base map
+// output has pairs for is the same as in the base, so its output file is
+// linked from the base's output directory instead of being reduced again.
+
+// checksumFiles returns the SHA-256 checksum of every file, in hex.
+func checksumFiles(files []string) (map[string]string, error) {
+	sums := make(map[string]string, len(files))
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file, err)
+		}
+		sums[file] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
+
+// findBase returns the latest successful incremental job among jobs whose
+// output a job with spec can reuse, or nil.
+func findBase(spec *pb.JobSpec, jobs []*Job) *Job {
+	for i := len(jobs) - 1; i >= 0; i-- {
+		j := jobs[i]
+		if j.state == pb.JobState_JOB_SUCCEEDED && j.checksums != nil && sameComputation(j.Spec, spec) {
+			return j
+		}
+	}
+	return nil
+}
+
+// sameComputation reports whether two specs compute the same output from the
+// same input files: they only differ in where the output goes.
+func sameComputation(a, b *pb.JobSpec) bool {
+	a, b = proto.Clone(a).(*pb.JobSpec), proto.Clone(b).(*pb.JobSpec)
+	a.OutputDir, b.OutputDir = "", ""
+	return proto.Equal(a, b)
+}
+
+// baseTask returns the completed map task of the base job with the same
+// split of an unchanged file as map task t, or nil.
+func (j *Job) baseTask(t *task) *task {
+	if j.base == nil || j.checksums[t.input.file] != j.base.checksums[t.input.file] {
+		return nil
+	}
+	for _, bt := range j.base.mapTasks {
+		if bt.input == t.input && bt.state == Completed {
+			return bt
+		}
+	}
+	return nil
+}
+
+// reuseMaps completes every map task that has the same split of an
+// unchanged file as a task of the base job with the base task's output.
+func (j *Job) reuseMaps() {
+	for _, t := range j.mapTasks {
+		bt := j.baseTask(t)
+		if bt == nil {
+			continue
+		}
+		t.reuse(bt)
+		t.location = bt.location
+		t.rawBytes, t.bytes = bt.rawBytes, bt.bytes
+		t.outputDir, t.outputID = bt.output()
+	}
+	fmt.Printf("[%s] Reusing the output of %d of %d map tasks of %s\n", j.ID, countReused(j.mapTasks), len(j.mapTasks), j.base.ID)
+}
+
+// reuse completes t with the output and statistics of task bt of the base
+// job.
+func (t *task) reuse(bt *task) {
+	t.state = Completed
+	t.committed = bt.committed
+	t.reusedFrom = bt.job.ID
+	if bt.reusedFrom != "" {
+		t.reusedFrom = bt.reusedFrom
+	}
+	t.worker, t.seconds, t.peak, t.counters = bt.worker, bt.seconds, bt.peak, bt.counters
+	t.uncompressed, t.compressed = bt.uncompressed, bt.compressed
+}
+
+// output returns the intermediate directory of a completed map task's output
+// and the task ID it was written under.
+func (t *task) output() (string, int) {
+	if t.reusedFrom == "" {
+		return t.job.intermediateDir, t.id
+	}
+	return t.outputDir, t.outputID
+}
+
+// reuseOutputs completes the reduce tasks of an incremental job whose
+// partition is the same as in the base job, once all map tasks completed, by
+// linking the base's output files into the job's output directory. Caller
+// holds m.mu.
+func (m *Master) reuseOutputs(j *Job) {
+	if j.base == nil || j.outputsReused || !allCompleted(j.mapTasks) {
+		return
+	}
+	j.outputsReused = true
+	// Map outputs that are new, or that the base had and this job does not.
+	var changed []*task
+	kept := make(map[*task]bool)
+	for _, t := range j.mapTasks {
+		if bt := j.baseTask(t); bt != nil {
+			kept[bt] = true
+		} else {
+			changed = append(changed, t)
+		}
+	}
+	for _, bt := range j.base.mapTasks {
+		if !kept[bt] {
+			changed = append(changed, bt)
+		}
+	}
+	for _, t := range j.reduceTasks {
+		if t.state != Idle || t.attempts > 0 || touchesPartition(changed, t.id) {
+			continue
+		}
+		if err := linkFile(j.base.outputFile(t.id), j.outputFile(t.id)); err != nil {
+			fmt.Printf("[%s] Cannot reuse %s of %s, reducing it again: %v\n", j.ID, filepath.Base(j.outputFile(t.id)), j.base.ID, err)
+			continue
+		}
+		t.reuse(j.base.reduceTasks[t.id])
+		m.recordTask(t)
+	}
+	fmt.Printf("[%s] Reusing the output of %d of %d reduce tasks of %s\n", j.ID, countReused(j.reduceTasks), len(j.reduceTasks), j.base.ID)
+}
+
+// touchesPartition reports whether any of the map tasks wrote pairs for
+// reduce partition r. Tasks without counters are assumed to have.
+func touchesPartition(tasks []*task, r int) bool {
+	for _, t := range tasks {
+		if n, ok := t.counters[apps.MapPartitionBytes+strconv.Itoa(r)]; n > 0 || !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func countReused(tasks []*task) int {
+	n := 0
+	for _, t := range tasks {
+		if t.reusedFrom != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// linkFile makes dst a hard link to src, or a copy where links are not
+// possible. It does nothing if both are the same file.
+func linkFile(src, dst string) error {
+	if filepath.Clean(src) == filepath.Clean(dst) {
+		_, err := os.Stat(src)
+		return err
+	}
+	os.Remove(dst)
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
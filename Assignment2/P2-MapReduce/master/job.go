@@ -0,0 +1,375 @@
+package master
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+	pb "github.com/example/protofiles"
+	"github.com/example/security"
+)
+
+// Job is one MapReduce job run by the master. Every job has its own
+// intermediate and output directories, so several jobs can run at once.
+type Job struct {
+	ID              string
+	Spec            *pb.JobSpec
+	intermediateDir string
+	outputDir       string
+	boundaries      []string  // range partitioner boundaries, if the job uses one
+	pipeline        *Pipeline // pipeline the job is a stage of, if any
+
+	// Incremental jobs only, see incremental.go.
+	checksums     map[string]string // SHA-256 of every input file
+	base          *Job              // job whose output is reused, if any
+	outputsReused bool              // reuseOutputs has run
+
+	mapTasks    []*task
+	reduceTasks []*task
+	state       pb.JobState
+	err         error // why the job failed or was cancelled
+	started     time.Time
+	ended       time.Time
+	mapsDone    bool
+	backups     int // backup attempts launched
+	dataLocal   int // attempts started on a worker holding their input
+	done        chan struct{}
+}
+
+// validateSpec checks the parts of a job spec that do not depend on its
+// input files.
+func validateSpec(spec *pb.JobSpec) error {
+	if spec.App == "" {
+		return fmt.Errorf("no app given")
+	}
+	if spec.NumReducers < 1 || spec.NumReducers > apps.MaxReducers {
+		return fmt.Errorf("need 1 to %d reducers, got %d", apps.MaxReducers, spec.NumReducers)
+	}
+	// Apps loaded only by the workers are checked when their tasks run.
+	app, err := apps.New(spec.App, spec.Params)
+	if err != nil {
+		if _, lookupErr := apps.Lookup(spec.App); lookupErr == nil {
+			return err
+		}
+	}
+	if f, ok := app.(apps.InputFormatter); ok && spec.InputFormat != "" {
+		if base, _ := formats.CutCodec(spec.InputFormat); base != f.InputFormat() {
+			return fmt.Errorf("%s reads its input as %s, not %s", spec.App, f.InputFormat(), base)
+		}
+	}
+	if _, err := formats.LookupInput(spec.InputFormat); err != nil {
+		return err
+	}
+	if _, err := formats.LookupOutput(spec.OutputFormat); err != nil {
+		return err
+	}
+	if _, err := formats.LookupCodec(spec.IntermediateCodec); err != nil {
+		return err
+	}
+	_, err = apps.NewPartitioner(spec.Partitioner, nil)
+	return err
+}
+
+// newJob validates a job spec, splits its input and creates its directories
+// under workDir/<id>. Input files and the output directory must be inside
+// inputRoot unless it is empty.
+// An incremental job reuses the output of the latest matching job among
+// earlier ones.
+func newJob(id string, spec *pb.JobSpec, workDir, inputRoot string, earlier []*Job) (*Job, error) {
+	if err := validateSpec(spec); err != nil {
+		return nil, err
+	}
+	if app, err := apps.New(spec.App, spec.Params); err == nil {
+		if f, ok := app.(apps.InputFormatter); ok && spec.InputFormat == "" {
+			spec.InputFormat = f.InputFormat()
+		}
+		if p, ok := app.(apps.PartitioningApp); ok && spec.Partitioner == "" {
+			spec.Partitioner = p.Partitioning()
+		}
+	}
+	files := spec.InputFiles
+	if len(files) == 0 {
+		var err error
+		if files, err = filepath.Glob(spec.InputGlob); err != nil {
+			return nil, fmt.Errorf("bad input glob %q: %v", spec.InputGlob, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no input files match %q", spec.InputGlob)
+		}
+	}
+	for _, file := range files {
+		if err := security.CheckPath(inputRoot, file); err != nil {
+			return nil, err
+		}
+	}
+	splitSize := spec.SplitSize
+	if splitSize <= 0 {
+		splitSize = DefaultSplitSize
+	}
+	input, err := formats.LookupInput(spec.InputFormat)
+	if err != nil {
+		return nil, err
+	}
+	splits, err := splitInputs(files, splitSize, input.Splittable())
+	if err != nil {
+		return nil, fmt.Errorf("failed to split input: %v", err)
+	}
+	var checksums map[string]string
+	var base *Job
+	if spec.Incremental {
+		if checksums, err = checksumFiles(files); err != nil {
+			return nil, err
+		}
+		base = findBase(spec, earlier)
+	}
+	var boundaries []string
+	if base != nil {
+		// Partitions must be the same as the base's to reuse its output.
+		boundaries = base.boundaries
+	} else if spec.Partitioner == apps.RangePartitioning {
+		if boundaries, err = sampleBoundaries(spec, input, splits); err != nil {
+			return nil, err
+		}
+	}
+
+	outputDir := spec.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(workDir, id, "output")
+	}
+	// Workers write the output next to the input.
+	if err := security.CheckPath(inputRoot, outputDir); err != nil {
+		return nil, err
+	}
+	j, err := buildJob(id, spec, splits, filepath.Join(workDir, id, "intermediate"), outputDir)
+	if err != nil {
+		return nil, err
+	}
+	j.boundaries = boundaries
+	j.checksums = checksums
+	fmt.Printf("[%s] Split %d input files into %d map tasks\n", id, len(files), len(splits))
+	if boundaries != nil {
+		fmt.Printf("[%s] Range partition boundaries: %q\n", id, boundaries)
+	}
+	if base != nil {
+		j.base = base
+		j.reuseMaps()
+	}
+	return j, nil
+}
+
+// buildJob creates a running job with one map task per split and its output
+// directory.
+func buildJob(id string, spec *pb.JobSpec, splits []split, intermediateDir, outputDir string) (*Job, error) {
+	j := &Job{
+		ID:              id,
+		Spec:            spec,
+		intermediateDir: intermediateDir,
+		outputDir:       outputDir,
+		state:           pb.JobState_JOB_RUNNING,
+		started:         time.Now(),
+		done:            make(chan struct{}),
+	}
+	// The intermediate directory is created by the workers, on their own
+	// machines.
+	if err := os.MkdirAll(j.outputDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	for i, s := range splits {
+		j.mapTasks = append(j.mapTasks, &task{job: j, kind: pb.TaskType_MAP_TASK, id: i, input: s, running: make(map[int]*attempt)})
+	}
+	for i := 0; i < int(spec.NumReducers); i++ {
+		j.reduceTasks = append(j.reduceTasks, &task{job: j, kind: pb.TaskType_REDUCE_TASK, id: i, running: make(map[int]*attempt)})
+	}
+	return j, nil
+}
+
+// Wait blocks until the job is over. It returns nil if the job succeeded.
+func (j *Job) Wait() error {
+	<-j.done
+	return j.err
+}
+
+// OutputDir is where the job's out-<reduce>.txt files are written.
+func (j *Job) OutputDir() string {
+	return j.outputDir
+}
+
+// outputFile returns the name reduce task r's output is committed under,
+// out-<r><ext> in the output directory.
+func (j *Job) outputFile(r int) string {
+	ext := ""
+	// The spec's output format was checked when the job was submitted.
+	if format, err := formats.LookupOutput(j.Spec.OutputFormat); err == nil {
+		ext = format.Ext()
+	}
+	return filepath.Join(j.outputDir, fmt.Sprintf("out-%d%s", r, ext))
+}
+
+func (j *Job) running() bool {
+	return j.state == pb.JobState_JOB_RUNNING
+}
+
+// finish ends the job in the given state. Running attempts are dropped, so
+// workers are told to abort them on their next heartbeat. Caller holds m.mu.
+func (j *Job) finish(state pb.JobState, err error) {
+	j.state = state
+	j.err = err
+	j.ended = time.Now()
+	for _, t := range j.allTasks() {
+		t.running = make(map[int]*attempt)
+	}
+	close(j.done)
+}
+
+// allTasks returns the map tasks followed by the reduce tasks.
+func (j *Job) allTasks() []*task {
+	tasks := make([]*task, 0, len(j.mapTasks)+len(j.reduceTasks))
+	tasks = append(tasks, j.mapTasks...)
+	return append(tasks, j.reduceTasks...)
+}
+
+// currentPhase returns the map tasks until they have all completed, then the
+// reduce tasks.
+func (j *Job) currentPhase() []*task {
+	if !allCompleted(j.mapTasks) {
+		return j.mapTasks
+	}
+	return j.reduceTasks
+}
+
+// findTask returns the task of the given type and ID, or nil.
+func (j *Job) findTask(kind pb.TaskType, id int32) *task {
+	var tasks []*task
+	if kind == pb.TaskType_MAP_TASK {
+		tasks = j.mapTasks
+	} else if kind == pb.TaskType_REDUCE_TASK {
+		tasks = j.reduceTasks
+	}
+	if id < 0 || int(id) >= len(tasks) {
+		return nil
+	}
+	return tasks[id]
+}
+
+// printIntermediateBytes reports how much the combiner and the codec shrank
+// the map output.
+func (j *Job) printIntermediateBytes() {
+	var raw, written, compressed int64
+	for _, t := range j.mapTasks {
+		raw += t.rawBytes
+		written += t.bytes
+		compressed += t.compressed
+	}
+	msg := fmt.Sprintf("[%s] Intermediate data: %d bytes", j.ID, written)
+	if raw > written {
+		msg += fmt.Sprintf(", %d without the combiner", raw)
+	}
+	if compressed > 0 {
+		msg += fmt.Sprintf(", %d compressed with %s (ratio %.2f)", compressed, j.Spec.IntermediateCodec, ratio(written, compressed))
+	}
+	fmt.Println(msg)
+}
+
+// ratio is the compression ratio of data compressed from uncompressed to
+// compressed bytes, or 0 if it was not compressed.
+func ratio(uncompressed, compressed int64) float64 {
+	if compressed == 0 {
+		return 0
+	}
+	return float64(uncompressed) / float64(compressed)
+}
+
+// status reports the job's state and per-phase progress.
+func (j *Job) status() *pb.JobStatus {
+	end := j.ended
+	if j.running() {
+		end = time.Now()
+	}
+	st := &pb.JobStatus{
+		JobId:          j.ID,
+		State:          j.state,
+		Spec:           j.Spec,
+		Map:            phaseProgress(j.mapTasks),
+		Reduce:         phaseProgress(j.reduceTasks),
+		OutputDir:      j.outputDir,
+		ElapsedSeconds: end.Sub(j.started).Seconds(),
+		BackupAttempts: int32(j.backups),
+	}
+	if j.err != nil {
+		st.Error = j.err.Error()
+	}
+	if j.base != nil {
+		st.BaseJob = j.base.ID
+	}
+	st.Counters = j.counters()
+	for _, t := range j.allTasks() {
+		if t.state == Completed {
+			st.Tasks = append(st.Tasks, &pb.TaskStats{Type: t.kind, TaskId: int32(t.id), Attempt: int32(t.committed),
+				Worker: t.worker, Seconds: t.seconds, BufferedBytesPeak: t.peak, Counters: t.counters,
+				UncompressedBytes: t.uncompressed, CompressedBytes: t.compressed, CompressionRatio: ratio(t.uncompressed, t.compressed),
+				ReusedFrom: t.reusedFrom})
+		}
+	}
+	return st
+}
+
+// counters adds up the counters of every completed task.
+func (j *Job) counters() map[string]int64 {
+	sum := make(map[string]int64)
+	for _, t := range j.allTasks() {
+		if t.state != Completed {
+			continue
+		}
+		for name, n := range t.counters {
+			sum[name] += n
+		}
+	}
+	return sum
+}
+
+// printCounters prints the job's counters in name order.
+func (j *Job) printCounters() {
+	counters := j.counters()
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("[%s] Counters:\n", j.ID)
+	for _, name := range names {
+		fmt.Printf("  %-28s %d\n", name, counters[name])
+	}
+}
+
+func phaseProgress(tasks []*task) *pb.PhaseProgress {
+	p := &pb.PhaseProgress{Total: int32(len(tasks))}
+	var done float64
+	for _, t := range tasks {
+		switch t.state {
+		case Completed:
+			p.Completed++
+			done++
+			if t.reusedFrom != "" {
+				p.Reused++
+			}
+		case InProgress:
+			p.InProgress++
+			best := 0.0
+			for _, a := range t.running {
+				if a.progress > best {
+					best = a.progress
+				}
+			}
+			done += best
+		}
+	}
+	if len(tasks) > 0 {
+		p.Progress = done / float64(len(tasks))
+	}
+	return p
+}
@@ -0,0 +1,47 @@
+package master
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/example/protofiles"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SubmitJob starts a job and returns its ID.
+func (m *Master) SubmitJob(ctx context.Context, spec *pb.JobSpec) (*pb.SubmitJobResponse, error) {
+	j, err := m.Submit(spec)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return &pb.SubmitJobResponse{JobId: j.ID}, nil
+}
+
+// GetJobStatus reports a job's state and the progress of its phases.
+func (m *Master) GetJobStatus(ctx context.Context, req *pb.JobStatusRequest) (*pb.JobStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[req.JobId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown job %q", req.JobId)
+	}
+	return j.status(), nil
+}
+
+// CancelJob stops a running job. Its running attempts are aborted and its
+// output directory is left as it is.
+func (m *Master) CancelJob(ctx context.Context, req *pb.CancelJobRequest) (*pb.JobStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[req.JobId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown job %q", req.JobId)
+	}
+	if !j.running() {
+		return nil, status.Errorf(codes.FailedPrecondition, "job %s is already %s", j.ID, j.state)
+	}
+	fmt.Printf("[%s] Job cancelled\n", j.ID)
+	m.endJob(j, pb.JobState_JOB_CANCELLED, fmt.Errorf("job %s was cancelled", j.ID))
+	return j.status(), nil
+}
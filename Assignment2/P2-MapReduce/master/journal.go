@@ -0,0 +1,422 @@
+package master
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	pb "github.com/example/protofiles"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// The journal is a write-ahead log of job and task state changes, one JSON
+// entry per line: job submissions (with their splits, so a restarted master
+// runs the same map tasks), started and committed task attempts, and the end
+// of a job. After a restart, tasks that were running are idle again; started
+// attempts are logged so that their numbers, and so their files, are never
+// reused by a new attempt, together with the number of attempts excused from
+// the task's limit, which is logged again whenever it grows. Committed map attempts are logged with the
+// address of the worker holding their output; if that output turns out to
+// be gone, reducers report it and the map task runs again. Worker
+// registrations are logged for the same reason as attempts: a worker that
+// outlived the old master must not share its ID with a worker of the new
+// one. Pipelines are logged with their stages when they are submitted and
+// with the job of every stage when it starts. An incremental job is logged
+// with its input checksums and base job, and its reused tasks with the job
+// they were reused from.
+const (
+	entryWorker   = "worker"
+	entrySubmit   = "submit"
+	entryStart    = "start"
+	entryTask     = "task"
+	entryEnd      = "end"
+	entryPipeline = "pipeline"
+	entryStage    = "stage"
+)
+
+type journalEntry struct {
+	Type string    `json:"type"`
+	Job  string    `json:"job,omitempty"`
+	Time time.Time `json:"time"`
+
+	// worker
+	Worker string `json:"worker,omitempty"`
+
+	// submit
+	Spec            json.RawMessage   `json:"spec,omitempty"`
+	Splits          []journalSplit    `json:"splits,omitempty"`
+	Boundaries      []string          `json:"boundaries,omitempty"`
+	IntermediateDir string            `json:"intermediate_dir,omitempty"` // also of a reused map output
+	OutputDir       string            `json:"output_dir,omitempty"`
+	Checksums       map[string]string `json:"checksums,omitempty"`
+	Base            string            `json:"base,omitempty"`
+
+	// start, task
+	Kind         pb.TaskType      `json:"kind,omitempty"`
+	Task         int              `json:"task,omitempty"`
+	Attempt      int              `json:"attempt,omitempty"`
+	Excused      int              `json:"excused,omitempty"` // attempts not counted, see task.exhausted
+	Address      string           `json:"address,omitempty"`
+	RawBytes     int64            `json:"raw_bytes,omitempty"`
+	Bytes        int64            `json:"bytes,omitempty"`
+	Seconds      float64          `json:"seconds,omitempty"`
+	Peak         int64            `json:"buffered_bytes_peak,omitempty"`
+	Uncompressed int64            `json:"uncompressed_bytes,omitempty"`
+	Compressed   int64            `json:"compressed_bytes,omitempty"`
+	Counters     map[string]int64 `json:"counters,omitempty"`
+	From         string           `json:"from,omitempty"`   // job a reused task's output is from
+	Output       int              `json:"output,omitempty"` // task ID a reused map output was written under
+
+	// end
+	State pb.JobState `json:"state,omitempty"`
+	Error string      `json:"error,omitempty"`
+
+	// pipeline, stage
+	Pipeline string            `json:"pipeline,omitempty"`
+	Stages   []json.RawMessage `json:"stages,omitempty"`
+	Stage    int               `json:"stage,omitempty"`
+}
+
+type journalSplit struct {
+	File   string `json:"file"`
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+}
+
+// OpenJournal rebuilds the master's jobs from the journal at path, if it
+// exists, and then logs every later state change to it. Jobs that were
+// running resume with only their unfinished tasks. It must be called before
+// Run and before the master serves requests.
+func (m *Master) OpenJournal(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries, err := readJournal(path)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := m.replay(e); err != nil {
+			return fmt.Errorf("journal %s: %v", path, err)
+		}
+	}
+	// A pipeline logged without its first stage, by a crash in between, is
+	// dropped; its first job runs on its own.
+	pipelines := m.pipelineOrder[:0]
+	for _, p := range m.pipelineOrder {
+		if len(p.jobs) > 0 {
+			pipelines = append(pipelines, p)
+		} else {
+			delete(m.pipelines, p.ID)
+		}
+	}
+	m.pipelineOrder = pipelines
+	for _, j := range m.jobOrder {
+		if j.running() {
+			checkOutputs(j)
+			fmt.Printf("[%s] Recovered: %d/%d map and %d/%d reduce tasks already completed\n",
+				j.ID, countCompleted(j.mapTasks), len(j.mapTasks), countCompleted(j.reduceTasks), len(j.reduceTasks))
+		}
+	}
+
+	// Compact the journal to the recovered state before appending to it.
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	m.journal, m.journalErr = f, nil
+	if m.nextWorkerID > 0 {
+		m.recordWorker(fmt.Sprintf("worker-%d", m.nextWorkerID))
+	}
+	for _, j := range m.jobOrder {
+		m.recordJob(j)
+	}
+	for _, p := range m.pipelineOrder {
+		m.recordPipeline(p)
+	}
+	// On failure the old journal is kept as it is.
+	err = m.journalErr
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		m.journal = nil
+		os.Remove(tmp)
+		return fmt.Errorf("failed to compact journal %s: %v", path, err)
+	}
+	if m.journal, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644); err != nil {
+		return err
+	}
+	// A pipeline whose stage succeeded just before the crash moves on.
+	for _, p := range m.pipelineOrder {
+		if p.state() == pb.JobState_JOB_RUNNING {
+			m.checkPipeline(p)
+		}
+	}
+	return nil
+}
+
+func readJournal(path string) ([]journalEntry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []journalEntry
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var e journalEntry
+		if err := dec.Decode(&e); err != nil {
+			// A torn last entry from a crash mid-write is dropped.
+			if err != io.EOF {
+				fmt.Printf("Ignoring the rest of journal %s: %v\n", path, err)
+			}
+			return entries, nil
+		}
+		entries = append(entries, e)
+	}
+}
+
+// replay applies one journal entry. Caller holds m.mu.
+func (m *Master) replay(e journalEntry) error {
+	if e.Type == entryWorker {
+		var n int
+		if _, err := fmt.Sscanf(e.Worker, "worker-%d", &n); err == nil && n > m.nextWorkerID {
+			m.nextWorkerID = n
+		}
+		return nil
+	}
+	if e.Type == entrySubmit {
+		spec := &pb.JobSpec{}
+		if err := protojson.Unmarshal(e.Spec, spec); err != nil {
+			return fmt.Errorf("bad spec for %s: %v", e.Job, err)
+		}
+		splits := make([]split, len(e.Splits))
+		for i, s := range e.Splits {
+			splits[i] = split{file: s.File, offset: s.Offset, length: s.Length}
+		}
+		j, err := buildJob(e.Job, spec, splits, e.IntermediateDir, e.OutputDir)
+		if err != nil {
+			return err
+		}
+		j.started = e.Time
+		j.boundaries = e.Boundaries
+		j.checksums = e.Checksums
+		j.base = m.jobs[e.Base]
+		m.jobs[j.ID] = j
+		m.jobOrder = append(m.jobOrder, j)
+		var n int
+		if _, err := fmt.Sscanf(j.ID, "job-%d", &n); err == nil && n > m.nextJobID {
+			m.nextJobID = n
+		}
+		return nil
+	}
+
+	if e.Type == entryPipeline {
+		p := &Pipeline{ID: e.Pipeline}
+		for _, raw := range e.Stages {
+			spec := &pb.JobSpec{}
+			if err := protojson.Unmarshal(raw, spec); err != nil {
+				return fmt.Errorf("bad stage for %s: %v", e.Pipeline, err)
+			}
+			p.stages = append(p.stages, spec)
+		}
+		m.pipelines[p.ID] = p
+		m.pipelineOrder = append(m.pipelineOrder, p)
+		var n int
+		if _, err := fmt.Sscanf(p.ID, "pipeline-%d", &n); err == nil && n > m.nextPipelineID {
+			m.nextPipelineID = n
+		}
+		return nil
+	}
+
+	j, ok := m.jobs[e.Job]
+	if !ok {
+		return fmt.Errorf("entry for unknown job %s", e.Job)
+	}
+	switch e.Type {
+	case entryStage:
+		p, ok := m.pipelines[e.Pipeline]
+		if !ok || e.Stage > len(p.jobs) || e.Stage >= len(p.stages) {
+			return fmt.Errorf("entry for unknown stage %d of %s", e.Stage+1, e.Pipeline)
+		}
+		j.pipeline = p
+		p.jobs = append(p.jobs[:e.Stage], j)
+	case entryStart, entryTask:
+		t := j.findTask(e.Kind, int32(e.Task))
+		if t == nil {
+			return fmt.Errorf("entry for unknown %s %d of %s", e.Kind, e.Task, e.Job)
+		}
+		if e.Attempt > t.attempts {
+			t.attempts = e.Attempt
+		}
+		if e.Excused > t.excused {
+			t.excused = e.Excused
+		}
+		if e.Type == entryTask {
+			t.state = Completed
+			t.committed = e.Attempt
+			t.location = e.Address
+			t.rawBytes, t.bytes = e.RawBytes, e.Bytes
+			t.worker, t.seconds, t.peak, t.counters = e.Worker, e.Seconds, e.Peak, e.Counters
+			t.uncompressed, t.compressed = e.Uncompressed, e.Compressed
+			t.reusedFrom, t.outputDir, t.outputID = e.From, e.IntermediateDir, e.Output
+		}
+	case entryEnd:
+		var err error
+		if e.Error != "" {
+			err = errors.New(e.Error)
+		}
+		j.finish(e.State, err)
+		j.ended = e.Time
+	}
+	return nil
+}
+
+// checkOutputs sets the completed reduce tasks of a recovered job whose
+// output file is gone, e.g. deleted while the master was down, back to idle,
+// so they run again instead of the job succeeding without them. Map output
+// is not checked here; it lives on the workers, and reducers report it lost.
+func checkOutputs(j *Job) {
+	for _, t := range j.reduceTasks {
+		if t.state != Completed {
+			continue
+		}
+		if _, err := os.Stat(j.outputFile(t.id)); err != nil {
+			fmt.Printf("%s output %s is gone, running it again\n", t, j.outputFile(t.id))
+			t.state = Idle
+			t.reusedFrom = ""
+			t.excused++
+		}
+	}
+}
+
+func countCompleted(tasks []*task) int {
+	n := 0
+	for _, t := range tasks {
+		if t.state == Completed {
+			n++
+		}
+	}
+	return n
+}
+
+// recordWorker logs a worker registration. Caller holds m.mu.
+func (m *Master) recordWorker(id string) {
+	m.record(journalEntry{Type: entryWorker, Worker: id})
+}
+
+// recordJob logs a job's submission and its current state. Caller holds m.mu.
+func (m *Master) recordJob(j *Job) {
+	spec, _ := protojson.Marshal(j.Spec)
+	e := journalEntry{
+		Type:            entrySubmit,
+		Job:             j.ID,
+		Time:            j.started,
+		Spec:            spec,
+		IntermediateDir: j.intermediateDir,
+		OutputDir:       j.outputDir,
+		Boundaries:      j.boundaries,
+		Checksums:       j.checksums,
+	}
+	if j.base != nil {
+		e.Base = j.base.ID
+	}
+	for _, t := range j.mapTasks {
+		e.Splits = append(e.Splits, journalSplit{File: t.input.file, Offset: t.input.offset, Length: t.input.length})
+	}
+	m.record(e)
+	for _, t := range j.allTasks() {
+		if t.attempts > 0 {
+			m.recordStart(t)
+		}
+		if t.state == Completed {
+			m.recordTask(t)
+		}
+	}
+	if !j.running() {
+		m.recordEnd(j)
+	}
+}
+
+// recordPipeline logs a pipeline's stages and the job of every stage started
+// so far. Caller holds m.mu.
+func (m *Master) recordPipeline(p *Pipeline) {
+	e := journalEntry{Type: entryPipeline, Pipeline: p.ID}
+	for _, spec := range p.stages {
+		raw, _ := protojson.Marshal(spec)
+		e.Stages = append(e.Stages, raw)
+	}
+	m.record(e)
+	for i := range p.jobs {
+		m.recordStage(p, i)
+	}
+}
+
+// recordStage logs the job of a pipeline stage. Caller holds m.mu.
+func (m *Master) recordStage(p *Pipeline, i int) {
+	m.record(journalEntry{Type: entryStage, Pipeline: p.ID, Stage: i, Job: p.jobs[i].ID})
+}
+
+// recordStart logs the latest attempt started for a task and how many of its
+// attempts are excused. Caller holds m.mu.
+func (m *Master) recordStart(t *task) {
+	m.record(journalEntry{Type: entryStart, Job: t.job.ID, Kind: t.kind, Task: t.id, Attempt: t.attempts, Excused: t.excused})
+}
+
+// recordTask logs a committed task attempt. Caller holds m.mu.
+func (m *Master) recordTask(t *task) {
+	m.record(journalEntry{Type: entryTask, Job: t.job.ID, Time: time.Now(), Kind: t.kind, Task: t.id,
+		Attempt: t.committed, Excused: t.excused, Address: t.location, RawBytes: t.rawBytes, Bytes: t.bytes,
+		Worker: t.worker, Seconds: t.seconds, Peak: t.peak, Counters: t.counters,
+		Uncompressed: t.uncompressed, Compressed: t.compressed,
+		From: t.reusedFrom, IntermediateDir: t.outputDir, Output: t.outputID})
+}
+
+// recordEnd logs the end of a job. Caller holds m.mu.
+func (m *Master) recordEnd(j *Job) {
+	e := journalEntry{Type: entryEnd, Job: j.ID, Time: j.ended, State: j.state}
+	if j.err != nil {
+		e.Error = j.err.Error()
+	}
+	m.record(e)
+}
+
+// record appends an entry to the journal and syncs it to disk, keeping the
+// first error in m.journalErr. Without a journal it does nothing. Caller
+// holds m.mu.
+func (m *Master) record(e journalEntry) {
+	if m.journal == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	line, err := json.Marshal(e)
+	if err == nil {
+		_, err = m.journal.Write(append(line, '\n'))
+	}
+	if err == nil {
+		err = m.journal.Sync()
+	}
+	if err != nil {
+		fmt.Printf("Failed to write journal: %v\n", err)
+		if m.journalErr == nil {
+			m.journalErr = err
+		}
+	}
+}
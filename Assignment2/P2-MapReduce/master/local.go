@@ -0,0 +1,50 @@
+package master
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// LocalWorkerDir holds the intermediate files of workers started by
+// StartWorkers, in a directory of their own per worker, as if every worker
+// ran on a machine of its own.
+const LocalWorkerDir = "workers"
+
+// StartWorkers launches n long-lived worker processes on this machine that
+// pull tasks from the master at masterAddr, passing them args as extra flags.
+func StartWorkers(n int, masterAddr, plugins string, args ...string) []*exec.Cmd {
+	var cmds []*exec.Cmd
+	for i := 0; i < n; i++ {
+		dir := filepath.Join(LocalWorkerDir, fmt.Sprintf("local-%d", i+1))
+		cmd := exec.Command("go", append([]string{"run", "server/main.go", "-master", masterAddr, "-plugin", plugins, "-dir", dir}, args...)...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			fmt.Printf("Failed to start worker %d: %v\n", i, err)
+			continue
+		}
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+// WaitWorkers gives the workers time to pick up the exit signal, then kills stragglers.
+func WaitWorkers(cmds []*exec.Cmd, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		for _, cmd := range cmds {
+			cmd.Wait()
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		for _, cmd := range cmds {
+			cmd.Process.Kill()
+		}
+	}
+}
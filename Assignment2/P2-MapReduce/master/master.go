@@ -0,0 +1,605 @@
+package master
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/example/protofiles"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultTaskTimeout      = 2 * time.Minute
+	DefaultHeartbeatTimeout = 5 * time.Second
+	DefaultMaxAttempts      = 4
+	DefaultBackupDelay      = 3 * time.Second
+	DefaultWorkDir          = "jobs"
+)
+
+// workerInfo is a registered long-lived worker.
+type workerInfo struct {
+	id            string
+	address       string
+	lastHeartbeat time.Time
+	mapSlots      int      // map attempts the worker runs at once
+	reduceSlots   int      // reduce attempts the worker runs at once
+	localData     []string // input paths on the worker's local disk
+}
+
+// slots returns how many attempts of a kind the worker runs at once.
+func (w *workerInfo) slots(kind pb.TaskType) int {
+	if kind == pb.TaskType_MAP_TASK {
+		return w.mapSlots
+	}
+	return w.reduceSlots
+}
+
+// hasLocal reports whether file is on the worker's local disk. Workers
+// report absolute paths; relative input paths are resolved against the
+// master's working directory.
+func (w *workerInfo) hasLocal(file string) bool {
+	file, _ = filepath.Abs(file)
+	for _, p := range w.localData {
+		if file == p || strings.HasPrefix(file, p+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Master runs MapReduce jobs on long-lived workers that pull tasks with
+// RequestTask. Jobs are submitted with SubmitJob and scheduled in submission
+// order. For every job the master tracks the state of each task, re-assigns
+// tasks whose worker fails, stops heartbeating or runs past the task timeout,
+// and only hands out reduce tasks once every map task has completed.
+//
+// When no task is idle, idle workers get backup attempts of the slowest
+// running tasks. The first attempt to finish is committed and the others are
+// told to abort.
+type Master struct {
+	pb.UnimplementedMasterServer
+
+	TaskTimeout      time.Duration // maximum duration of one task attempt
+	HeartbeatTimeout time.Duration // silence after which a worker is considered dead
+	MaxAttempts      int           // attempts per task before the job fails
+	Speculative      bool          // launch backup attempts for stragglers
+	BackupDelay      time.Duration // minimum runtime of an attempt before it gets a backup
+	WorkDir          string        // jobs get their directories under WorkDir/<job ID>
+	WorkerToken      string        // token workers must register with, "" to accept any
+	InputRoot        string        // directory the input files of jobs must be in, "" for anywhere
+
+	mu             sync.Mutex
+	jobs           map[string]*Job
+	jobOrder       []*Job // in submission order
+	nextJobID      int
+	workers        map[string]*workerInfo // keyed by worker ID
+	nextWorkerID   int
+	pipelines      map[string]*Pipeline
+	pipelineOrder  []*Pipeline // in submission order
+	nextPipelineID int
+	shutdown       bool     // workers are told to exit
+	journal        *os.File // write-ahead log of job and task state, see OpenJournal
+	journalErr     error    // first failed write to the journal, see record
+}
+
+// New creates a master with no jobs.
+func New() *Master {
+	return &Master{
+		TaskTimeout:      DefaultTaskTimeout,
+		HeartbeatTimeout: DefaultHeartbeatTimeout,
+		MaxAttempts:      DefaultMaxAttempts,
+		Speculative:      true,
+		BackupDelay:      DefaultBackupDelay,
+		WorkDir:          DefaultWorkDir,
+		jobs:             make(map[string]*Job),
+		workers:          make(map[string]*workerInfo),
+		pipelines:        make(map[string]*Pipeline),
+	}
+}
+
+// Submit starts a job. The job's input is split right away, so a bad spec is
+// reported here rather than when the job runs.
+func (m *Master) Submit(spec *pb.JobSpec) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.submit(spec)
+}
+
+// submit starts a job. Caller holds m.mu.
+func (m *Master) submit(spec *pb.JobSpec) (*Job, error) {
+	m.nextJobID++
+	j, err := newJob(fmt.Sprintf("job-%d", m.nextJobID), spec, m.WorkDir, m.InputRoot, m.jobOrder)
+	if err != nil {
+		return nil, err
+	}
+	m.jobs[j.ID] = j
+	m.jobOrder = append(m.jobOrder, j)
+	m.recordJob(j)
+	m.reuseOutputs(j)
+	input := spec.InputGlob
+	if len(spec.InputFiles) > 0 {
+		input = fmt.Sprintf("%d files in %s", len(spec.InputFiles), filepath.Dir(spec.InputFiles[0]))
+	}
+	fmt.Printf("[%s] Submitted: app %s, input %s, %d reducers, output in %s\n", j.ID, spec.App, input, spec.NumReducers, j.outputDir)
+	return j, nil
+}
+
+// Shutdown tells every worker to exit on its next RequestTask.
+func (m *Master) Shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shutdown = true
+}
+
+// Run monitors workers and jobs until Shutdown is called.
+func (m *Master) Run() {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.mu.Lock()
+		if m.shutdown {
+			m.mu.Unlock()
+			return
+		}
+		m.checkWorkers()
+		for _, j := range m.jobOrder {
+			if j.running() {
+				m.checkJob(j)
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// checkJob fails a job with a task that has no attempts left, and finishes
+// it once every reduce task completed. Caller holds m.mu.
+func (m *Master) checkJob(j *Job) {
+	for _, t := range j.allTasks() {
+		if t.state == Idle && t.exhausted(m.MaxAttempts) {
+			err := fmt.Errorf("%s failed after %d attempts", t, t.attempts)
+			fmt.Printf("[%s] Job failed: %v\n", j.ID, err)
+			m.endJob(j, pb.JobState_JOB_FAILED, err)
+			return
+		}
+	}
+	if !j.mapsDone && allCompleted(j.mapTasks) {
+		j.mapsDone = true
+		fmt.Printf("[%s] All map tasks completed\n", j.ID)
+		j.printIntermediateBytes()
+	}
+	m.reuseOutputs(j)
+	if allCompleted(j.reduceTasks) {
+		fmt.Printf("[%s] All reduce tasks completed (%d backup attempts launched, %d attempts data-local)\n", j.ID, j.backups, j.dataLocal)
+		j.printCounters()
+		m.endJob(j, pb.JobState_JOB_SUCCEEDED, nil)
+	}
+}
+
+// endJob finishes a job and logs it. If the job is a stage of a pipeline
+// and succeeded, the next stage is started. Caller holds m.mu.
+func (m *Master) endJob(j *Job, state pb.JobState, err error) {
+	j.finish(state, err)
+	m.recordEnd(j)
+	if j.pipeline != nil {
+		m.checkPipeline(j.pipeline)
+	}
+}
+
+// checkWorkers drops attempts whose worker died or that are past the task
+// timeout, and puts tasks without a running attempt back to idle. Caller
+// holds m.mu.
+func (m *Master) checkWorkers() {
+	for id, w := range m.workers {
+		if time.Since(w.lastHeartbeat) > m.HeartbeatTimeout {
+			fmt.Printf("Worker %s (%s) stopped sending heartbeats\n", id, w.address)
+			delete(m.workers, id)
+			for _, t := range m.runningTasks() {
+				if t.kind == pb.TaskType_MAP_TASK && t.state == Completed && t.location == w.address {
+					m.rerunMap(t, fmt.Sprintf("on %s is lost", id))
+				}
+			}
+		}
+	}
+	for _, t := range m.runningTasks() {
+		if t.state != InProgress {
+			continue
+		}
+		for n, a := range t.running {
+			reason := ""
+			if _, ok := m.workers[a.worker]; !ok {
+				reason = "worker died"
+			} else if time.Since(a.started) > m.TaskTimeout {
+				reason = "timed out"
+			}
+			if reason != "" {
+				fmt.Printf("%s attempt %d on worker %s %s\n", t, n, a.worker, reason)
+				delete(t.running, n)
+			}
+		}
+		if len(t.running) == 0 {
+			fmt.Printf("%s has no running attempt, re-assigning\n", t)
+			t.state = Idle
+		}
+	}
+}
+
+// rerunMap puts a completed map task whose output is lost back to idle, as
+// long as some reduce task of its job still needs the output. Caller holds
+// m.mu.
+func (m *Master) rerunMap(t *task, reason string) {
+	if t.state != Completed || allCompleted(t.job.reduceTasks) {
+		return
+	}
+	fmt.Printf("%s output %s, running it again\n", t, reason)
+	t.state = Idle
+	t.location = ""
+	t.reusedFrom = ""
+	t.excused++
+	m.recordStart(t)
+	t.job.mapsDone = false
+}
+
+// runningTasks returns the tasks of every running job. Caller holds m.mu.
+func (m *Master) runningTasks() []*task {
+	var tasks []*task
+	for _, j := range m.jobOrder {
+		if j.running() {
+			tasks = append(tasks, j.allTasks()...)
+		}
+	}
+	return tasks
+}
+
+func allCompleted(tasks []*task) bool {
+	for _, t := range tasks {
+		if t.state != Completed {
+			return false
+		}
+	}
+	return true
+}
+
+// nextTask picks an idle task of a kind worker w has a free slot for, or nil
+// if there is none yet, and reports whether the task's input is local to w.
+// Jobs are served in submission order, and a job's reduce tasks are only
+// handed out once all of its map tasks have completed. Within a job, the task
+// with the most input on w is preferred. Caller holds m.mu.
+func (m *Master) nextTask(w *workerInfo, free map[pb.TaskType]bool) (*task, bool) {
+	for _, j := range m.jobOrder {
+		if !j.running() {
+			continue
+		}
+		var best *task
+		var bestLocal int64
+		for _, t := range j.currentPhase() {
+			if t.state != Idle || t.exhausted(m.MaxAttempts) || !free[t.kind] {
+				continue
+			}
+			if local := t.localBytes(w); best == nil || local > bestLocal {
+				best, bestLocal = t, local
+			}
+		}
+		if best != nil {
+			return best, bestLocal > 0
+		}
+	}
+	return nil, false
+}
+
+// runningOn counts the attempts of a kind the master has running on a
+// worker. Caller holds m.mu.
+func (m *Master) runningOn(worker string, kind pb.TaskType) int {
+	n := 0
+	for _, t := range m.runningTasks() {
+		if t.kind != kind {
+			continue
+		}
+		for _, a := range t.running {
+			if a.worker == worker {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func localNote(local bool) string {
+	if local {
+		return " (data-local)"
+	}
+	return ""
+}
+
+// backupTask picks the running task that is expected to finish last, for a
+// backup attempt on worker. Only tasks in a phase without idle tasks, with a
+// single attempt that has run for at least BackupDelay, are considered.
+// Caller holds m.mu.
+func (m *Master) backupTask(worker string, free map[pb.TaskType]bool) *task {
+	if !m.Speculative {
+		return nil
+	}
+	now := time.Now()
+	var best *task
+	var bestLeft time.Duration
+	for _, j := range m.jobOrder {
+		if !j.running() || hasIdle(j.currentPhase()) {
+			continue
+		}
+		for _, t := range j.currentPhase() {
+			if t.state != InProgress || !free[t.kind] || len(t.running) != 1 || t.exhausted(m.MaxAttempts) || t.runsOn(worker) {
+				continue
+			}
+			for _, a := range t.running {
+				if now.Sub(a.started) < m.BackupDelay {
+					continue
+				}
+				if left := a.estimatedLeft(now); best == nil || left > bestLeft {
+					best, bestLeft = t, left
+				}
+			}
+		}
+	}
+	return best
+}
+
+func hasIdle(tasks []*task) bool {
+	for _, t := range tasks {
+		if t.state == Idle {
+			return true
+		}
+	}
+	return false
+}
+
+// findTask returns the task of a running job, or nil. Caller holds m.mu.
+func (m *Master) findTask(jobID string, kind pb.TaskType, id int32) *task {
+	j, ok := m.jobs[jobID]
+	if !ok || !j.running() {
+		return nil
+	}
+	return j.findTask(kind, id)
+}
+
+// RegisterWorker adds a long-lived worker to the pool and returns its ID.
+// If the master has a WorkerToken, only workers sending it may join.
+func (m *Master) RegisterWorker(ctx context.Context, req *pb.RegisterWorkerRequest) (*pb.RegisterWorkerResponse, error) {
+	if m.WorkerToken != "" && subtle.ConstantTimeCompare([]byte(req.Token), []byte(m.WorkerToken)) != 1 {
+		fmt.Printf("Rejected worker at %s: invalid registration token\n", req.Address)
+		return nil, status.Errorf(codes.PermissionDenied, "invalid registration token")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextWorkerID++
+	id := fmt.Sprintf("worker-%d", m.nextWorkerID)
+	m.workers[id] = &workerInfo{
+		id:            id,
+		address:       req.Address,
+		lastHeartbeat: time.Now(),
+		mapSlots:      max(int(req.MapSlots), 1),
+		reduceSlots:   max(int(req.ReduceSlots), 1),
+		localData:     req.LocalData,
+	}
+	m.recordWorker(id)
+	fmt.Printf("Registered %s at %s with %d map and %d reduce slots\n", id, req.Address, max(req.MapSlots, 1), max(req.ReduceSlots, 1))
+	return &pb.RegisterWorkerResponse{WorkerId: id}, nil
+}
+
+// RequestTask hands the next idle task to the calling worker, or a backup
+// attempt of a straggler if no task is idle. Only tasks of a kind the worker
+// has a free slot for are considered, going by both the worker's own count and
+// the attempts the master has running on it.
+func (m *Master) RequestTask(ctx context.Context, req *pb.TaskRequest) (*pb.TaskAssignment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.workers[req.WorkerId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown worker %s", req.WorkerId)
+	}
+	w.lastHeartbeat = time.Now()
+	if m.shutdown {
+		return &pb.TaskAssignment{Type: pb.TaskType_EXIT_TASK}, nil
+	}
+
+	free := map[pb.TaskType]bool{
+		pb.TaskType_MAP_TASK:    req.FreeMapSlots > 0 && m.runningOn(w.id, pb.TaskType_MAP_TASK) < w.mapSlots,
+		pb.TaskType_REDUCE_TASK: req.FreeReduceSlots > 0 && m.runningOn(w.id, pb.TaskType_REDUCE_TASK) < w.reduceSlots,
+	}
+	t, local := m.nextTask(w, free)
+	backup := false
+	if t == nil {
+		if t = m.backupTask(w.id, free); t == nil {
+			return &pb.TaskAssignment{Type: pb.TaskType_NO_TASK}, nil
+		}
+		backup = true
+		t.job.backups++
+	}
+	if local {
+		t.job.dataLocal++
+	}
+	t.attempts++
+	t.state = InProgress
+	t.running[t.attempts] = &attempt{worker: w.id, started: time.Now()}
+	m.recordStart(t)
+
+	if backup {
+		fmt.Printf("Launching backup attempt %d of %s on %s\n", t.attempts, t, w.id)
+	}
+	j := t.job
+	assignment := &pb.TaskAssignment{Type: t.kind, Attempt: int32(t.attempts), JobId: j.ID}
+	if t.kind == pb.TaskType_MAP_TASK {
+		fmt.Printf("Assigning %s for %s to %s%s\n", t, t.input, w.id, localNote(local))
+		assignment.Map = &pb.MapRequest{
+			MapTaskId:           int32(t.id),
+			Filename:            t.input.file,
+			Offset:              t.input.offset,
+			Length:              t.input.length,
+			NumReducers:         j.Spec.NumReducers,
+			Mode:                j.Spec.App,
+			Attempt:             int32(t.attempts),
+			JobId:               j.ID,
+			IntermediateDir:     j.intermediateDir,
+			Partitioner:         j.Spec.Partitioner,
+			PartitionBoundaries: j.boundaries,
+			InputFormat:         j.Spec.InputFormat,
+			Params:              j.Spec.Params,
+			IntermediateCodec:   j.Spec.IntermediateCodec,
+		}
+	} else {
+		fmt.Printf("Assigning %s to %s%s\n", t, w.id, localNote(local))
+		mapAttempts := make([]int32, len(j.mapTasks))
+		mapAddresses := make([]string, len(j.mapTasks))
+		var mapDirs []string
+		var mapOutputIDs []int32
+		for i, mt := range j.mapTasks {
+			mapAttempts[i] = int32(mt.committed)
+			mapAddresses[i] = mt.location
+		}
+		if countReused(j.mapTasks) > 0 {
+			for _, mt := range j.mapTasks {
+				dir, id := mt.output()
+				mapDirs = append(mapDirs, dir)
+				mapOutputIDs = append(mapOutputIDs, int32(id))
+			}
+		}
+		assignment.Reduce = &pb.ReduceRequest{
+			ReduceTaskId:      int32(t.id),
+			Mode:              j.Spec.App,
+			Attempt:           int32(t.attempts),
+			MapAttempts:       mapAttempts,
+			MapAddresses:      mapAddresses,
+			OutputFormat:      j.Spec.OutputFormat,
+			JobId:             j.ID,
+			IntermediateDir:   j.intermediateDir,
+			OutputDir:         j.outputDir,
+			Params:            j.Spec.Params,
+			IntermediateCodec: j.Spec.IntermediateCodec,
+			MapDirs:           mapDirs,
+			MapOutputIds:      mapOutputIDs,
+		}
+	}
+	return assignment, nil
+}
+
+// ReportTaskDone records the outcome of a task attempt. Only the first
+// successful attempt of a task is accepted; reports for attempts that were
+// re-assigned, that lost to another attempt, or whose job is over, are not.
+func (m *Master) ReportTaskDone(ctx context.Context, req *pb.TaskReport) (*pb.TaskReportAck, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.workers[req.WorkerId]
+	if ok {
+		w.lastHeartbeat = time.Now()
+	}
+
+	t := m.findTask(req.JobId, req.Type, req.TaskId)
+	if t == nil {
+		return &pb.TaskReportAck{Accepted: false}, nil
+	}
+	a, running := t.running[int(req.Attempt)]
+	if t.state != InProgress || !running || !ok || a.worker != req.WorkerId {
+		return &pb.TaskReportAck{Accepted: false}, nil
+	}
+	delete(t.running, int(req.Attempt))
+
+	result := req.Result
+	if result.GetSuccess() && t.kind == pb.TaskType_REDUCE_TASK {
+		if err := commitOutput(t, int(req.Attempt), result.OutputFile); err != nil {
+			result = &pb.TaskResponse{Success: false, Message: err.Error()}
+		}
+	}
+	if !result.GetSuccess() {
+		fmt.Printf("%s failed on %s (attempt %d): %s, will retry\n", t, req.WorkerId, req.Attempt, result.GetMessage())
+		if len(result.LostMapOutputs) > 0 {
+			// The reducer is not to blame; the maps it could not fetch run again.
+			t.excused++
+			m.recordStart(t)
+			for id, lostAttempt := range result.LostMapOutputs {
+				if mt := t.job.findTask(pb.TaskType_MAP_TASK, id); mt != nil && mt.committed == int(lostAttempt) {
+					m.rerunMap(mt, "could not be fetched")
+				}
+			}
+		}
+		if len(t.running) == 0 {
+			t.state = Idle
+		}
+		return &pb.TaskReportAck{Accepted: true}, nil
+	}
+
+	t.state = Completed
+	t.committed = int(req.Attempt)
+	t.location = w.address
+	t.rawBytes = result.RawIntermediateBytes
+	t.bytes = result.IntermediateBytes
+	t.worker = req.WorkerId
+	t.seconds = time.Since(a.started).Seconds()
+	t.peak = result.BufferedBytesPeak
+	t.uncompressed, t.compressed = result.UncompressedBytes, result.CompressedBytes
+	t.counters = result.Counters
+	m.recordTask(t)
+	fmt.Printf("%s completed by %s (attempt %d): %s\n", t, req.WorkerId, req.Attempt, result.GetMessage())
+	if t.kind == pb.TaskType_MAP_TASK {
+		// Before any reduce task of the job is handed out.
+		m.reuseOutputs(t.job)
+	}
+	for n, other := range t.running {
+		fmt.Printf("Aborting attempt %d of %s on %s\n", n, t, other.worker)
+	}
+	t.running = make(map[int]*attempt)
+	return &pb.TaskReportAck{Accepted: true}, nil
+}
+
+// commitOutput renames reduce task t's attempt output,
+// out-<reduce>-attempt-<n><ext> in the job's output directory, to
+// out-<reduce><ext>. Any other file a worker reports is rejected, so a worker
+// cannot have the master move files elsewhere.
+func commitOutput(t *task, attempt int, attemptFile string) error {
+	if attemptFile == "" {
+		return fmt.Errorf("reduce task %d reported no output file", t.id)
+	}
+	final := t.job.outputFile(t.id)
+	ext := strings.TrimPrefix(filepath.Base(final), fmt.Sprintf("out-%d", t.id))
+	want := fmt.Sprintf("out-%d-attempt-%d%s", t.id, attempt, ext)
+	if filepath.Clean(filepath.Dir(attemptFile)) != filepath.Clean(t.job.outputDir) || filepath.Base(attemptFile) != want {
+		return fmt.Errorf("reduce task %d reported output file %s, want %s", t.id, attemptFile, filepath.Join(t.job.outputDir, want))
+	}
+	if err := os.Rename(attemptFile, final); err != nil {
+		return fmt.Errorf("failed to commit reduce output: %v", err)
+	}
+	return nil
+}
+
+// Heartbeat records that a worker is alive and the progress of its attempts.
+// Attempts the master no longer tracks, because another attempt of the task
+// already completed, the job is over, or the worker was declared dead or
+// registered with a master that has since restarted, are returned to be
+// aborted.
+func (m *Master) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, known := m.workers[req.WorkerId]
+	if known {
+		w.lastHeartbeat = time.Now()
+	}
+
+	res := &pb.HeartbeatResponse{Known: known}
+	for _, p := range req.Tasks {
+		t := m.findTask(p.JobId, p.Type, p.TaskId)
+		if t == nil || !known {
+			res.Abort = append(res.Abort, &pb.TaskProgress{JobId: p.JobId, Type: p.Type, TaskId: p.TaskId, Attempt: p.Attempt})
+			continue
+		}
+		if a, ok := t.running[int(p.Attempt)]; ok && a.worker == w.id {
+			a.progress = p.Progress
+		} else {
+			res.Abort = append(res.Abort, &pb.TaskProgress{JobId: p.JobId, Type: p.Type, TaskId: p.TaskId, Attempt: p.Attempt})
+		}
+	}
+	return res, nil
+}
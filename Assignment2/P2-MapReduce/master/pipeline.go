@@ -0,0 +1,184 @@
+package master
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/example/formats"
+	pb "github.com/example/protofiles"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Pipeline is a chain of jobs run one after the other. Every stage but the
+// first reads the out-* files of the stage before it. A stage's job is only
+// submitted once the previous stage succeeded, so its input is split like
+// that of any other job.
+type Pipeline struct {
+	ID     string
+	stages []*pb.JobSpec // as submitted
+	jobs   []*Job        // job of every stage started so far
+	err    error         // why the next stage could not be started
+}
+
+// SubmitPipeline checks every stage and starts the first one.
+func (m *Master) SubmitPipeline(ctx context.Context, spec *pb.PipelineSpec) (*pb.SubmitPipelineResponse, error) {
+	if len(spec.Stages) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "a pipeline needs at least one stage")
+	}
+	for i, stage := range spec.Stages {
+		if i == 0 && stage.InputGlob == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "stage 1 has no input glob")
+		}
+		if i > 0 && (stage.InputGlob != "" || len(stage.InputFiles) > 0) {
+			return nil, status.Errorf(codes.InvalidArgument, "stage %d reads the output of stage %d and takes no input glob", i+1, i)
+		}
+		if err := validateSpec(stage); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "stage %d: %v", i+1, err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, err := m.submit(proto.Clone(spec.Stages[0]).(*pb.JobSpec))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "stage 1: %v", err)
+	}
+	m.nextPipelineID++
+	p := &Pipeline{ID: fmt.Sprintf("pipeline-%d", m.nextPipelineID), stages: spec.Stages, jobs: []*Job{j}}
+	j.pipeline = p
+	m.pipelines[p.ID] = p
+	m.pipelineOrder = append(m.pipelineOrder, p)
+	m.recordPipeline(p)
+	fmt.Printf("[%s] Submitted %d stages, stage 1 is %s\n", p.ID, len(p.stages), j.ID)
+	return &pb.SubmitPipelineResponse{PipelineId: p.ID}, nil
+}
+
+// GetPipelineStatus reports a pipeline's state and the status of the job of
+// every stage started so far.
+func (m *Master) GetPipelineStatus(ctx context.Context, req *pb.PipelineRequest) (*pb.PipelineStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.pipelines[req.PipelineId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown pipeline %q", req.PipelineId)
+	}
+	return p.status(), nil
+}
+
+// CancelPipeline cancels the job of the running stage. The pipeline can be
+// restarted from that stage with RestartPipeline.
+func (m *Master) CancelPipeline(ctx context.Context, req *pb.PipelineRequest) (*pb.PipelineStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.pipelines[req.PipelineId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown pipeline %q", req.PipelineId)
+	}
+	if st := p.state(); st != pb.JobState_JOB_RUNNING {
+		return nil, status.Errorf(codes.FailedPrecondition, "pipeline %s is already %s", p.ID, st)
+	}
+	j := p.jobs[len(p.jobs)-1]
+	if !j.running() {
+		return nil, status.Errorf(codes.FailedPrecondition, "pipeline %s is between stages", p.ID)
+	}
+	fmt.Printf("[%s] Job cancelled with %s\n", j.ID, p.ID)
+	m.endJob(j, pb.JobState_JOB_CANCELLED, fmt.Errorf("pipeline %s was cancelled", p.ID))
+	return p.status(), nil
+}
+
+// RestartPipeline runs a failed or cancelled pipeline again from its first
+// stage that did not succeed. The output of the earlier stages is kept.
+func (m *Master) RestartPipeline(ctx context.Context, req *pb.PipelineRequest) (*pb.PipelineStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.pipelines[req.PipelineId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown pipeline %q", req.PipelineId)
+	}
+	if st := p.state(); st == pb.JobState_JOB_RUNNING || st == pb.JobState_JOB_SUCCEEDED {
+		return nil, status.Errorf(codes.FailedPrecondition, "pipeline %s is %s", p.ID, st)
+	}
+	p.err = nil
+	stage := len(p.jobs)
+	if p.jobs[stage-1].state != pb.JobState_JOB_SUCCEEDED {
+		stage--
+	}
+	fmt.Printf("[%s] Restarting from stage %d\n", p.ID, stage+1)
+	m.startStage(p, stage)
+	return p.status(), nil
+}
+
+// checkPipeline starts the next stage of a pipeline whose current stage
+// succeeded. Caller holds m.mu.
+func (m *Master) checkPipeline(p *Pipeline) {
+	last := p.jobs[len(p.jobs)-1]
+	if p.err != nil || last.running() {
+		return
+	}
+	if last.state != pb.JobState_JOB_SUCCEEDED {
+		fmt.Printf("[%s] Stopped at stage %d: %s %s\n", p.ID, len(p.jobs), last.ID, last.state)
+		return
+	}
+	if len(p.jobs) == len(p.stages) {
+		fmt.Printf("[%s] All %d stages completed, output in %s\n", p.ID, len(p.stages), last.outputDir)
+		return
+	}
+	m.startStage(p, len(p.jobs))
+}
+
+// startStage submits the job of stage i, reading the committed output files
+// of stage i-1, in place of any earlier job of that stage. Unless the stage
+// names an input format, the output is read in the format it was written in.
+// Caller holds m.mu.
+func (m *Master) startStage(p *Pipeline, i int) {
+	spec := proto.Clone(p.stages[i]).(*pb.JobSpec)
+	if i > 0 {
+		prev := p.jobs[i-1]
+		// Not a glob over the output directory: it may still hold the files
+		// of reduce attempts that were never committed.
+		for _, t := range prev.reduceTasks {
+			spec.InputFiles = append(spec.InputFiles, prev.outputFile(t.id))
+		}
+		if out := prev.Spec.OutputFormat; spec.InputFormat == "" && out != "" && out != formats.Text {
+			spec.InputFormat = out
+		}
+	}
+	j, err := m.submit(spec)
+	if err != nil {
+		p.err = fmt.Errorf("stage %d could not be started: %v", i+1, err)
+		fmt.Printf("[%s] %v\n", p.ID, p.err)
+		return
+	}
+	j.pipeline = p
+	p.jobs = append(p.jobs[:i], j)
+	m.recordStage(p, i)
+	fmt.Printf("[%s] Stage %d/%d started as %s\n", p.ID, i+1, len(p.stages), j.ID)
+}
+
+// state is the state of the job of the current stage, except that a
+// pipeline is only done when its last stage is.
+func (p *Pipeline) state() pb.JobState {
+	if p.err != nil {
+		return pb.JobState_JOB_FAILED
+	}
+	last := p.jobs[len(p.jobs)-1]
+	if last.state == pb.JobState_JOB_SUCCEEDED && len(p.jobs) < len(p.stages) {
+		return pb.JobState_JOB_RUNNING
+	}
+	return last.state
+}
+
+func (p *Pipeline) status() *pb.PipelineStatus {
+	st := &pb.PipelineStatus{PipelineId: p.ID, State: p.state(), NumStages: int32(len(p.stages))}
+	for _, j := range p.jobs {
+		st.Stages = append(st.Stages, j.status())
+	}
+	if last := p.jobs[len(p.jobs)-1]; p.err != nil {
+		st.Error = p.err.Error()
+	} else if last.err != nil {
+		st.Error = fmt.Sprintf("stage %d (%s): %v", len(p.jobs), last.ID, last.err)
+	}
+	return st
+}
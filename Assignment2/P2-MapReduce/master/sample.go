@@ -0,0 +1,60 @@
+package master
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+	pb "github.com/example/protofiles"
+)
+
+// sampleBytes is how much input, from the start of every split, is mapped to
+// sample the intermediate keys of a range-partitioned job.
+const sampleBytes = 64 << 10
+
+var errSampled = errors.New("sampled enough records")
+
+// sampleBoundaries runs the job's map function over the first records of
+// every split and picks the range partitioner's boundaries from the keys it
+// emits. The app must be known to the master, so apps from plugins have to
+// be loaded by the master as well as by the workers.
+func sampleBoundaries(spec *pb.JobSpec, input formats.InputFormat, splits []split) ([]string, error) {
+	app, err := apps.New(spec.App, spec.Params)
+	if err != nil {
+		return nil, fmt.Errorf("range partitioning samples keys on the master: %v", err)
+	}
+	var keys []string
+	for _, s := range splits {
+		if err := sampleSplit(app, input, s, &keys); err != nil {
+			return nil, fmt.Errorf("failed to sample %s: %v", s, err)
+		}
+	}
+	return apps.SampleBoundaries(keys, int(spec.NumReducers)), nil
+}
+
+// sampleSplit maps the records in about the first sampleBytes of a split and
+// appends the keys to keys.
+func sampleSplit(app apps.MapReduceApp, input formats.InputFormat, s split, keys *[]string) error {
+	f, err := os.Open(s.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	read := 0
+	err = input.ReadRecords(io.NewSectionReader(f, s.offset, s.length), func(record string) error {
+		for _, kv := range app.Map(s.file, record) {
+			*keys = append(*keys, kv.Key)
+		}
+		if read += len(record); read >= sampleBytes {
+			return errSampled
+		}
+		return nil
+	})
+	if err == errSampled {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package master
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultSplitSize is the target size of one map task's input.
+const DefaultSplitSize = 64 << 20
+
+// split is a byte range of an input file processed by one map task. Splits
+// start at the beginning of a line and end right after a newline (or at the
+// end of the file), so no line is cut in two.
+type split struct {
+	file   string
+	offset int64
+	length int64
+}
+
+func (s split) String() string {
+	return fmt.Sprintf("%s [%d, %d)", s.file, s.offset, s.offset+s.length)
+}
+
+// splitInputs cuts every file into splits of about splitSize bytes. Files
+// that are not splittable become one split each.
+func splitInputs(files []string, splitSize int64, splittable bool) ([]split, error) {
+	var splits []split
+	for _, file := range files {
+		if !splittable {
+			info, err := os.Stat(file)
+			if err != nil {
+				return nil, err
+			}
+			splits = append(splits, split{file: file, length: info.Size()})
+			continue
+		}
+		fileSplits, err := splitFile(file, splitSize)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, fileSplits...)
+	}
+	return splits, nil
+}
+
+// splitFile cuts one file into splits. Each split is extended past splitSize
+// up to the end of the line it would otherwise cut. An empty file becomes a
+// single empty split.
+func splitFile(path string, splitSize int64) ([]split, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return []split{{file: path}}, nil
+	}
+
+	var splits []split
+	for start := int64(0); start < size; {
+		end := start + splitSize
+		if end >= size {
+			end = size
+		} else {
+			// Move the end to just after the first newline at or after end-1.
+			n, err := lineLength(bufio.NewReader(io.NewSectionReader(f, end-1, size-end+1)))
+			if err == io.EOF {
+				end = size
+			} else if err != nil {
+				return nil, err
+			} else {
+				end += n - 1
+			}
+		}
+		splits = append(splits, split{file: path, offset: start, length: end - start})
+		start = end
+	}
+	return splits, nil
+}
+
+// lineLength returns the number of bytes up to and including the next newline.
+func lineLength(r *bufio.Reader) (int64, error) {
+	var n int64
+	for {
+		line, err := r.ReadSlice('\n')
+		n += int64(len(line))
+		if err != bufio.ErrBufferFull {
+			return n, err
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package master
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/example/apps"
+	pb "github.com/example/protofiles"
+)
+
+// TaskState is the master's view of a task.
+type TaskState int
+
+const (
+	Idle TaskState = iota
+	InProgress
+	Completed
+)
+
+func (s TaskState) String() string {
+	switch s {
+	case Idle:
+		return "idle"
+	case InProgress:
+		return "in-progress"
+	default:
+		return "completed"
+	}
+}
+
+// attempt is one running execution of a task. A task has more than one
+// running attempt when a backup was launched for a straggler.
+type attempt struct {
+	worker   string    // ID of the worker running the attempt
+	started  time.Time // start of the attempt
+	progress float64   // last reported fraction done
+}
+
+// estimatedLeft extrapolates the attempt's remaining time from its progress
+// rate. Attempts that have not reported progress yet are assumed to be far
+// from done.
+func (a *attempt) estimatedLeft(now time.Time) time.Duration {
+	elapsed := now.Sub(a.started)
+	if a.progress <= 0 {
+		return math.MaxInt64
+	}
+	return time.Duration(float64(elapsed) * (1 - a.progress) / a.progress)
+}
+
+// task is one map or reduce task and the bookkeeping for its attempts.
+type task struct {
+	job       *Job
+	kind      pb.TaskType
+	id        int
+	input     split // input split, map tasks only
+	state     TaskState
+	attempts  int              // attempts started so far; also the number of the latest attempt
+	running   map[int]*attempt // running attempts by attempt number
+	committed int              // attempt whose output was kept, once completed
+	excused   int              // attempts not counted against MaxAttempts, see exhausted
+	location  string           // address of the worker that ran the completed attempt, where map output stays
+
+	rawBytes, bytes int64 // intermediate sizes reported by the completed map attempt
+
+	// Statistics of the completed attempt.
+	worker   string // ID of the worker that ran it
+	seconds  float64
+	peak     int64 // estimated peak of buffered bytes, see pb.TaskResponse
+	counters map[string]int64
+
+	uncompressed, compressed int64 // bytes written through the codec, see pb.TaskResponse
+
+	// Output reused from an earlier job, see incremental.go.
+	reusedFrom string // ID of the job that ran the task
+	outputDir  string // map only: intermediate directory of the output
+	outputID   int    // map only: task ID the output was written under
+}
+
+func (t *task) String() string {
+	if t.kind == pb.TaskType_MAP_TASK {
+		return fmt.Sprintf("%s map task %d", t.job.ID, t.id)
+	}
+	return fmt.Sprintf("%s reduce task %d", t.job.ID, t.id)
+}
+
+// localBytes estimates how much of the task's input is on worker w: the
+// whole split for a map task whose input file is in w's local data, and for
+// a reduce task the bytes of its partition written by map attempts that ran
+// on w. Caller holds m.mu.
+func (t *task) localBytes(w *workerInfo) int64 {
+	if t.kind == pb.TaskType_MAP_TASK {
+		if w.hasLocal(t.input.file) {
+			return max(t.input.length, 1)
+		}
+		return 0
+	}
+	var n int64
+	for _, mt := range t.job.mapTasks {
+		if mt.state == Completed && mt.location == w.address {
+			n += mt.counters[apps.MapPartitionBytes+strconv.Itoa(t.id)]
+		}
+	}
+	return n
+}
+
+// exhausted reports whether the task used up its attempts. Attempts that
+// failed through no fault of their own, such as a reduce attempt that could
+// not fetch map output from a dead worker, or a map attempt whose output was
+// lost with its worker, do not count.
+func (t *task) exhausted(maxAttempts int) bool {
+	return t.attempts-t.excused >= maxAttempts
+}
+
+// runsOn reports whether worker is running an attempt of the task.
+func (t *task) runsOn(worker string) bool {
+	for _, a := range t.running {
+		if a.worker == worker {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/example/apps"
+	"github.com/example/master"
+	pb "github.com/example/protofiles"
+	"github.com/example/security"
+	"google.golang.org/grpc"
+)
+
+// A long-running master. Jobs are submitted with mrctl, and workers are
+// started separately (or with -workers) and pull tasks from it. Job state is
+// journaled, so a restarted master resumes the jobs that were running.
+func main() {
+	port := flag.Int("port", 50051, "Port for the Master service")
+	numWorkers := flag.Int("workers", 0, "Number of local worker processes to start")
+	workDir := flag.String("work-dir", master.DefaultWorkDir, "Directory for the jobs' intermediate and default output directories")
+	speculative := flag.Bool("speculative", true, "Launch backup attempts for straggler tasks near the end of each phase")
+	plugins := flag.String("plugin", "", "Comma-separated list of MapReduce app plugins (.so) for the master and local workers to load")
+	journal := flag.String("journal", "", "Journal of job state to recover from and append to (default <work-dir>/master.wal, \"off\" to disable)")
+	httpAddr := flag.String("http", "localhost:8080", "Address for the status dashboard and JSON API, which is unauthenticated plain HTTP (\"\" to disable, \":8080\" for every interface)")
+	inputRoot := flag.String("input-root", ".", "Directory the input files of jobs must be in (\"\" to allow any file); also passed to local workers")
+	token := flag.String("token", os.Getenv(security.TokenEnv), "Token workers must register with (default $"+security.TokenEnv+", \"\" to accept any worker)")
+	tlsFlags := security.AddFlags()
+	flag.Parse()
+	creds, err := tlsFlags.Credentials()
+	if err != nil {
+		fmt.Printf("Failed to set up TLS: %v\n", err)
+		os.Exit(1)
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+	if err != nil {
+		fmt.Printf("Failed to listen: %v\n", err)
+		os.Exit(1)
+	}
+	// The master loads plugins too, to sample keys for range partitioning.
+	if _, err := apps.LoadPlugins(*plugins); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	m := master.New()
+	m.WorkDir = *workDir
+	m.Speculative = *speculative
+	m.WorkerToken = *token
+	m.InputRoot = *inputRoot
+	if *journal == "" {
+		*journal = filepath.Join(*workDir, "master.wal")
+	}
+	if *journal != "off" {
+		if err := m.OpenJournal(*journal); err != nil {
+			fmt.Printf("Failed to recover from journal: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	grpcServer := grpc.NewServer(grpc.Creds(creds))
+	pb.RegisterMasterServer(grpcServer, m)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			fmt.Printf("Failed to serve: %v\n", err)
+		}
+	}()
+	fmt.Printf("Master server listening on :%d\n", *port)
+
+	if *httpAddr != "" {
+		go master.ServeDashboard(*httpAddr, m)
+	}
+	go m.Run()
+	// Local workers inherit the token through the environment.
+	os.Setenv(security.TokenEnv, *token)
+	workers := master.StartWorkers(*numWorkers, fmt.Sprintf("localhost:%d", *port), *plugins, append(tlsFlags.Args(), "-input-root", *inputRoot)...)
+
+	// On Ctrl-C, tell the workers to exit before stopping.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+	fmt.Println("Shutting down")
+	m.Shutdown()
+	master.WaitWorkers(workers, 10*time.Second)
+	time.Sleep(time.Second)
+	grpcServer.Stop()
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/example/apps"
+	pb "github.com/example/protofiles"
+	"github.com/example/security"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const usage = `Usage: go run mrctl/main.go [-master addr] [-tls-ca ca.pem -tls-cert cert.pem -tls-key key.pem] <command> [args]
+
+Commands:
+  submit [-input glob] [-app name] [-reducers N] [-output dir] [-split-size bytes] [-partitioner hash|range]
+         [-input-format name] [-output-format name] [-param key=value]... [-wait]
+  pipeline [-wait] <spec.json>   run the stages of a PipelineSpec, e.g. pipelines/top_words.json
+  status [-tasks] <job-id|pipeline-id>   a job's status includes its counters, and with -tasks every completed task
+  cancel <job-id|pipeline-id>
+  restart [-wait] <pipeline-id>  run a failed or cancelled pipeline again from the stage that did not finish
+`
+
+func main() {
+	masterAddr := flag.String("master", "localhost:50051", "Master address")
+	tlsFlags := security.AddFlags()
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	creds, err := tlsFlags.Credentials()
+	if err != nil {
+		fmt.Printf("Failed to set up TLS: %v\n", err)
+		os.Exit(1)
+	}
+	conn, err := grpc.Dial(*masterAddr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		fmt.Printf("Failed to connect to master: %v\n", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+	client := pb.NewMasterClient(conn)
+
+	args := flag.Args()[1:]
+	switch flag.Arg(0) {
+	case "submit":
+		err = submit(client, args)
+	case "pipeline":
+		err = submitPipeline(client, args)
+	case "restart":
+		err = restartPipeline(client, args)
+	case "status":
+		fs := flag.NewFlagSet("status", flag.ExitOnError)
+		tasks := fs.Bool("tasks", false, "Also print the statistics of every completed task")
+		fs.Parse(args)
+		err = withJobID(fs.Args(), func(id string) error {
+			if isPipeline(id) {
+				st, err := client.GetPipelineStatus(context.Background(), &pb.PipelineRequest{PipelineId: id})
+				if err == nil {
+					printPipelineStatus(st)
+				}
+				return err
+			}
+			st, err := client.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: id})
+			if err == nil {
+				printStatus(st)
+				printCounters(st.Counters)
+				if *tasks {
+					printTasks(st.Tasks)
+				}
+			}
+			return err
+		})
+	case "cancel":
+		err = withJobID(args, func(id string) error {
+			if isPipeline(id) {
+				st, err := client.CancelPipeline(context.Background(), &pb.PipelineRequest{PipelineId: id})
+				if err == nil {
+					printPipelineStatus(st)
+				}
+				return err
+			}
+			st, err := client.CancelJob(context.Background(), &pb.CancelJobRequest{JobId: id})
+			if err == nil {
+				printStatus(st)
+			}
+			return err
+		})
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func withJobID(args []string, f func(id string) error) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a job ID")
+	}
+	return f(args[0])
+}
+
+// submit sends a job spec to the master and optionally waits for the job.
+func submit(client pb.MasterClient, args []string) error {
+	fs := flag.NewFlagSet("submit", flag.ExitOnError)
+	input := fs.String("input", "dataset/*.txt", "Glob of the input files")
+	app := fs.String("app", "word_count", "MapReduce app to run")
+	reducers := fs.Int("reducers", 2, "Number of reduce tasks")
+	output := fs.String("output", "", "Output directory (default: a directory of the job's own)")
+	splitSize := fs.Int64("split-size", 0, "Maximum input split size in bytes (default: the master's)")
+	partitioner := fs.String("partitioner", "", "Partitioner: hash, or range for totally ordered output across reducers (default: the app's, or hash)")
+	inputFormat := fs.String("input-format", "", "Input format: text, whole_file, csv or json_lines, optionally with +gzip, +zstd or +snappy (default: the app's, or text)")
+	outputFormat := fs.String("output-format", "text", "Output format: text, csv or json_lines, optionally with +gzip, +zstd or +snappy")
+	intermediateCodec := fs.String("intermediate-codec", "", "Codec of the intermediate files: none, gzip, zstd or snappy (default none)")
+	incremental := fs.Bool("incremental", false, "Reuse the output of the latest incremental job with the same spec for input files that did not change")
+	params := apps.Params{}
+	fs.Var(params, "param", "App parameter as key=value; repeat for several")
+	wait := fs.Bool("wait", false, "Wait for the job to finish, printing its progress")
+	fs.Parse(args)
+
+	res, err := client.SubmitJob(context.Background(), &pb.JobSpec{
+		InputGlob:         *input,
+		App:               *app,
+		NumReducers:       int32(*reducers),
+		OutputDir:         *output,
+		SplitSize:         *splitSize,
+		Partitioner:       *partitioner,
+		InputFormat:       *inputFormat,
+		OutputFormat:      *outputFormat,
+		IntermediateCodec: *intermediateCodec,
+		Incremental:       *incremental,
+		Params:            params,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Submitted %s\n", res.JobId)
+	if !*wait {
+		return nil
+	}
+
+	for {
+		time.Sleep(time.Second)
+		st, err := client.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: res.JobId})
+		if err != nil {
+			return err
+		}
+		printStatus(st)
+		switch st.State {
+		case pb.JobState_JOB_RUNNING:
+			continue
+		case pb.JobState_JOB_SUCCEEDED:
+			printCounters(st.Counters)
+			return nil
+		default:
+			return fmt.Errorf("job %s ended as %s", st.JobId, st.State)
+		}
+	}
+}
+
+// printCounters prints counters in name order.
+func printCounters(counters map[string]int64) {
+	if len(counters) == 0 {
+		return
+	}
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println("Counters:")
+	for _, name := range names {
+		fmt.Printf("  %-28s %d\n", name, counters[name])
+	}
+}
+
+// printTasks prints one line per completed task with its run time, worker
+// and counters.
+func printTasks(tasks []*pb.TaskStats) {
+	fmt.Println("Tasks:")
+	for _, t := range tasks {
+		names := make([]string, 0, len(t.Counters))
+		for name := range t.Counters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		counters := make([]string, len(names))
+		for i, name := range names {
+			counters[i] = fmt.Sprintf("%s=%d", name, t.Counters[name])
+		}
+		kind := "map"
+		if t.Type == pb.TaskType_REDUCE_TASK {
+			kind = "reduce"
+		}
+		extra := ""
+		if t.BufferedBytesPeak > 0 {
+			extra += fmt.Sprintf("  buffered peak %.1fMiB", float64(t.BufferedBytesPeak)/(1<<20))
+		}
+		if t.CompressedBytes > 0 {
+			extra += fmt.Sprintf("  compressed %d->%d bytes (%.2fx)", t.UncompressedBytes, t.CompressedBytes, t.CompressionRatio)
+		}
+		if t.ReusedFrom != "" {
+			extra += "  reused from " + t.ReusedFrom
+		}
+		fmt.Printf("  %s %d  attempt %d on %s  %.2fs%s  %s\n", kind, t.TaskId, t.Attempt, t.Worker, t.Seconds, extra, strings.Join(counters, " "))
+	}
+}
+
+func isPipeline(id string) bool {
+	return strings.HasPrefix(id, "pipeline-")
+}
+
+// submitPipeline sends the PipelineSpec in a JSON file to the master and
+// optionally waits for the pipeline.
+func submitPipeline(client pb.MasterClient, args []string) error {
+	fs := flag.NewFlagSet("pipeline", flag.ExitOnError)
+	wait := fs.Bool("wait", false, "Wait for the pipeline to finish, printing its progress")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected a pipeline spec file")
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	spec := &pb.PipelineSpec{}
+	if err := protojson.Unmarshal(data, spec); err != nil {
+		return fmt.Errorf("bad pipeline spec %s: %v", fs.Arg(0), err)
+	}
+	res, err := client.SubmitPipeline(context.Background(), spec)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Submitted %s\n", res.PipelineId)
+	if !*wait {
+		return nil
+	}
+	return waitPipeline(client, res.PipelineId)
+}
+
+func restartPipeline(client pb.MasterClient, args []string) error {
+	fs := flag.NewFlagSet("restart", flag.ExitOnError)
+	wait := fs.Bool("wait", false, "Wait for the pipeline to finish, printing its progress")
+	fs.Parse(args)
+	return withJobID(fs.Args(), func(id string) error {
+		st, err := client.RestartPipeline(context.Background(), &pb.PipelineRequest{PipelineId: id})
+		if err != nil {
+			return err
+		}
+		printPipelineStatus(st)
+		if !*wait {
+			return nil
+		}
+		return waitPipeline(client, id)
+	})
+}
+
+func waitPipeline(client pb.MasterClient, id string) error {
+	for {
+		time.Sleep(time.Second)
+		st, err := client.GetPipelineStatus(context.Background(), &pb.PipelineRequest{PipelineId: id})
+		if err != nil {
+			return err
+		}
+		printPipelineStatus(st)
+		switch st.State {
+		case pb.JobState_JOB_RUNNING:
+			continue
+		case pb.JobState_JOB_SUCCEEDED:
+			return nil
+		default:
+			return fmt.Errorf("pipeline %s ended as %s", st.PipelineId, st.State)
+		}
+	}
+}
+
+func printPipelineStatus(st *pb.PipelineStatus) {
+	fmt.Printf("%s %s  stage %d/%d\n", st.PipelineId, st.State, len(st.Stages), st.NumStages)
+	for _, job := range st.Stages {
+		fmt.Print("  ")
+		printStatus(job)
+	}
+	// A failed stage's error is already printed with its job.
+	if n := len(st.Stages); st.Error != "" && (n == 0 || st.Stages[n-1].Error == "") {
+		fmt.Printf("  error: %s\n", st.Error)
+	}
+}
+
+func printStatus(st *pb.JobStatus) {
+	fmt.Printf("%s %s  app=%s  map %d/%d (%.0f%%)  reduce %d/%d (%.0f%%)  %.1fs  output=%s\n",
+		st.JobId, st.State, st.Spec.GetApp(),
+		st.Map.GetCompleted(), st.Map.GetTotal(), 100*st.Map.GetProgress(),
+		st.Reduce.GetCompleted(), st.Reduce.GetTotal(), 100*st.Reduce.GetProgress(),
+		st.ElapsedSeconds, st.OutputDir)
+	if st.BaseJob != "" {
+		fmt.Printf("  incremental: reused %d map and %d reduce outputs of %s\n", st.Map.GetReused(), st.Reduce.GetReused(), st.BaseJob)
+	}
+	if st.BackupAttempts > 0 {
+		fmt.Printf("  backup attempts: %d\n", st.BackupAttempts)
+	}
+	if st.Error != "" {
+		fmt.Printf("  error: %s\n", st.Error)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+)
+
+const usage = `Usage: go run mrquery/main.go [-index dir] [-stopwords] [-stem] [query]
+
+Answers queries over the output of a positional_index job. Without a query,
+reads one query per line from standard input.
+
+Queries:
+  fox dog            documents containing both words (AND is implied)
+  fox OR cat         documents containing either word
+  fox AND NOT dog    documents containing fox but not dog
+  "lazy dog"         documents containing the phrase
+  (fox OR cat) AND "brown dog"
+
+-stopwords and -stem must match the parameters the index was built with.
+`
+
+// index is a loaded positional index.
+type index struct {
+	terms map[string]apps.PostingsList
+	docs  []string // every indexed document, sorted
+}
+
+func main() {
+	dir := flag.String("index", "output", "Output directory of the positional_index job")
+	stopWords := flag.Bool("stopwords", false, "The index was built with stopwords=true")
+	stem := flag.Bool("stem", false, "The index was built with stem=true")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	idx, err := loadIndex(*dir)
+	if err != nil {
+		fmt.Printf("Failed to load index: %v\n", err)
+		os.Exit(1)
+	}
+	tokenizer := apps.Tokenizer{StopWords: *stopWords, Stem: *stem}
+
+	if flag.NArg() > 0 {
+		if err := run(idx, tokenizer, strings.Join(flag.Args(), " ")); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Printf("Loaded %d terms in %d documents\n", len(idx.terms), len(idx.docs))
+	scanner := bufio.NewScanner(os.Stdin)
+	for fmt.Print("> "); scanner.Scan(); fmt.Print("> ") {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		if err := run(idx, tokenizer, scanner.Text()); err != nil {
+			fmt.Println(err)
+		}
+	}
+	fmt.Println()
+}
+
+// run answers one query, printing the matching documents and the lines the
+// query's words or phrases match on.
+func run(idx *index, tokenizer apps.Tokenizer, query string) error {
+	expr, err := parse(query, tokenizer)
+	if err != nil {
+		return err
+	}
+	result := expr.eval(idx)
+	docs := make([]string, 0, len(result))
+	for doc := range result {
+		docs = append(docs, doc)
+	}
+	sort.Strings(docs)
+	for _, doc := range docs {
+		if lines := result[doc]; len(lines) > 0 {
+			fmt.Printf("%s  lines %s\n", doc, formatLines(lines))
+		} else {
+			fmt.Println(doc)
+		}
+	}
+	fmt.Printf("%d of %d documents match\n", len(docs), len(idx.docs))
+	return nil
+}
+
+func formatLines(lines map[int]bool) string {
+	list := make([]int, 0, len(lines))
+	for l := range lines {
+		list = append(list, l)
+	}
+	sort.Ints(list)
+	s := make([]string, len(list))
+	for i, l := range list {
+		s[i] = fmt.Sprint(l)
+	}
+	return strings.Join(s, ", ")
+}
+
+// loadIndex reads the out-* files of a positional_index job written in the
+// text or json_lines output format, compressed or not.
+func loadIndex(dir string) (*index, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "out-*"))
+	if err != nil {
+		return nil, err
+	}
+	idx := &index{terms: make(map[string]apps.PostingsList)}
+	docs := make(map[string]bool)
+	for _, name := range files {
+		if strings.Contains(filepath.Base(name), "-attempt-") {
+			continue // left by an attempt the master did not keep
+		}
+		base, codec := formats.CutCodecExt(name)
+		var parse func(line string) (string, apps.PostingsList, error)
+		switch filepath.Ext(base) {
+		case ".txt":
+			parse = parseTextLine
+		case ".jsonl":
+			parse = parseJSONLine
+		default:
+			return nil, fmt.Errorf("%s: only the text and json_lines output formats can be queried", name)
+		}
+		if err := readIndexFile(name, codec, parse, idx, docs); err != nil {
+			return nil, err
+		}
+	}
+	if len(idx.terms) == 0 {
+		return nil, fmt.Errorf("no index entries in %s", dir)
+	}
+	for doc := range docs {
+		idx.docs = append(idx.docs, doc)
+	}
+	sort.Strings(idx.docs)
+	return idx, nil
+}
+
+func readIndexFile(name string, codec formats.Codec, parse func(string) (string, apps.PostingsList, error), idx *index, docs map[string]bool) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zr, err := codec.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	defer zr.Close()
+	r := bufio.NewReader(zr)
+	for n := 1; ; n++ {
+		line, err := r.ReadString('\n')
+		if line = strings.TrimSuffix(line, "\n"); line != "" {
+			term, list, perr := parse(line)
+			if perr != nil {
+				return fmt.Errorf("%s:%d: %v", name, n, perr)
+			}
+			idx.terms[term] = list
+			for _, p := range list.Postings {
+				docs[p.Doc] = true
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// parseTextLine parses a "term {postings list}" line.
+func parseTextLine(line string) (string, apps.PostingsList, error) {
+	var list apps.PostingsList
+	term, value, ok := strings.Cut(line, " ")
+	if !ok {
+		return "", list, fmt.Errorf("not a positional index entry")
+	}
+	err := json.Unmarshal([]byte(value), &list)
+	return term, list, err
+}
+
+// parseJSONLine parses a {"key": term, "value": {postings list}} line.
+func parseJSONLine(line string) (string, apps.PostingsList, error) {
+	var entry struct {
+		Key   string            `json:"key"`
+		Value apps.PostingsList `json:"value"`
+	}
+	err := json.Unmarshal([]byte(line), &entry)
+	return entry.Key, entry.Value, err
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/example/apps"
+)
+
+// result maps each matching document to the lines its words or phrases
+// matched on. A document matched only through NOT has no lines.
+type result map[string]map[int]bool
+
+// expr is a node of a parsed query.
+type expr interface {
+	eval(idx *index) result
+}
+
+type andExpr struct{ left, right expr }
+type orExpr struct{ left, right expr }
+type notExpr struct{ operand expr }
+
+// phraseExpr matches its terms at consecutive positions. offsets[i] is the
+// position of terms[i] relative to terms[0], which skips stop words left out
+// of the index. A single word is a phrase of one term.
+type phraseExpr struct {
+	terms   []string
+	offsets []int
+}
+
+func (e andExpr) eval(idx *index) result {
+	left, right := e.left.eval(idx), e.right.eval(idx)
+	r := make(result)
+	for doc, lines := range left {
+		if other, ok := right[doc]; ok {
+			r[doc] = union(lines, other)
+		}
+	}
+	return r
+}
+
+func (e orExpr) eval(idx *index) result {
+	r := make(result)
+	for _, side := range []result{e.left.eval(idx), e.right.eval(idx)} {
+		for doc, lines := range side {
+			r[doc] = union(r[doc], lines)
+		}
+	}
+	return r
+}
+
+func (e notExpr) eval(idx *index) result {
+	excluded := e.operand.eval(idx)
+	r := make(result)
+	for _, doc := range idx.docs {
+		if _, ok := excluded[doc]; !ok {
+			r[doc] = map[int]bool{}
+		}
+	}
+	return r
+}
+
+func (e phraseExpr) eval(idx *index) result {
+	// positions[i] maps each document to the positions of terms[i] in it.
+	positions := make([]map[string]map[int]bool, len(e.terms))
+	for i, term := range e.terms {
+		positions[i] = make(map[string]map[int]bool)
+		for _, p := range idx.terms[term].Postings {
+			set := make(map[int]bool, len(p.Positions))
+			for _, pos := range p.Positions {
+				set[pos.Pos] = true
+			}
+			positions[i][p.Doc] = set
+		}
+	}
+	r := make(result)
+	for _, p := range idx.terms[e.terms[0]].Postings {
+	next:
+		for _, start := range p.Positions {
+			for i := 1; i < len(e.terms); i++ {
+				if !positions[i][p.Doc][start.Pos+e.offsets[i]] {
+					continue next
+				}
+			}
+			if r[p.Doc] == nil {
+				r[p.Doc] = make(map[int]bool)
+			}
+			r[p.Doc][start.Line] = true
+		}
+	}
+	return r
+}
+
+func union(a, b map[int]bool) map[int]bool {
+	u := make(map[int]bool, len(a)+len(b))
+	for l := range a {
+		u[l] = true
+	}
+	for l := range b {
+		u[l] = true
+	}
+	return u
+}
+
+// parser is a recursive descent parser for the grammar
+//
+//	or      = and { "OR" and }
+//	and     = not { ["AND"] not }
+//	not     = "NOT" not | primary
+//	primary = "(" or ")" | phrase | word
+type parser struct {
+	tokens    []string
+	tokenizer apps.Tokenizer
+}
+
+// parse parses a query, normalising its words with tokenizer.
+func parse(query string, tokenizer apps.Tokenizer) (expr, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, tokenizer: tokenizer}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if len(p.tokens) > 0 {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[0])
+	}
+	return e, nil
+}
+
+// lex splits a query into parentheses, quoted phrases (kept with their
+// quotes) and words.
+func lex(query string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			end := strings.IndexByte(query[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated phrase %s", query[i:])
+			}
+			tokens = append(tokens, query[i:i+end+2])
+			i += end + 2
+		default:
+			end := strings.IndexAny(query[i:], " \t()\"")
+			if end < 0 {
+				end = len(query) - i
+			}
+			tokens = append(tokens, query[i:i+end])
+			i += end
+		}
+	}
+	return tokens, nil
+}
+
+func (p *parser) peek() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+	return p.tokens[0]
+}
+
+func (p *parser) next() string {
+	t := p.tokens[0]
+	p.tokens = p.tokens[1:]
+	return t
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "OR" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t != "" && t != "OR" && t != ")"; t = p.peek() {
+		if t == "AND" {
+			p.next()
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.peek() == "NOT" {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	switch t := p.peek(); t {
+	case "":
+		return nil, fmt.Errorf("unexpected end of query")
+	case ")", "AND", "OR":
+		return nil, fmt.Errorf("unexpected %q", t)
+	case "(":
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.next()
+		return e, nil
+	default:
+		return p.phrase(strings.Trim(p.next(), `"`))
+	}
+}
+
+// phrase normalises the words of a phrase or single word the way the index
+// was built. A word can turn into several terms, e.g. "e-mail".
+func (p *parser) phrase(text string) (expr, error) {
+	tokens := p.tokenizer.Tokenize(text)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%q has no indexed words", text)
+	}
+	e := phraseExpr{}
+	for _, tok := range tokens {
+		e.terms = append(e.terms, tok.Term)
+		e.offsets = append(e.offsets, tok.Pos-tokens[0].Pos)
+	}
+	return e, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+	"github.com/example/mrtest"
+	pb "github.com/example/protofiles"
+)
+
+// word is the phrase of one term that a single word parses to.
+func word(term string) phraseExpr {
+	return phraseExpr{terms: []string{term}, offsets: []int{0}}
+}
+
+func TestParse(t *testing.T) {
+	stopWords := apps.Tokenizer{StopWords: true}
+	for _, tc := range []struct {
+		query     string
+		tokenizer apps.Tokenizer
+		want      expr
+	}{
+		{"fox", apps.Tokenizer{}, word("fox")},
+		{"Fox.", apps.Tokenizer{}, word("fox")},
+		{"fox dog", apps.Tokenizer{}, andExpr{word("fox"), word("dog")}},
+		{"fox AND dog", apps.Tokenizer{}, andExpr{word("fox"), word("dog")}},
+		{"fox dog cat", apps.Tokenizer{}, andExpr{andExpr{word("fox"), word("dog")}, word("cat")}},
+		// AND binds tighter than OR.
+		{"fox OR cat dog", apps.Tokenizer{}, orExpr{word("fox"), andExpr{word("cat"), word("dog")}}},
+		{"fox dog OR cat", apps.Tokenizer{}, orExpr{andExpr{word("fox"), word("dog")}, word("cat")}},
+		// NOT binds tighter than AND.
+		{"NOT fox dog", apps.Tokenizer{}, andExpr{notExpr{word("fox")}, word("dog")}},
+		{"fox AND NOT dog", apps.Tokenizer{}, andExpr{word("fox"), notExpr{word("dog")}}},
+		{"NOT NOT fox", apps.Tokenizer{}, notExpr{notExpr{word("fox")}}},
+		{"NOT (fox OR dog)", apps.Tokenizer{}, notExpr{orExpr{word("fox"), word("dog")}}},
+		{`(fox OR cat) AND "brown dog"`, apps.Tokenizer{}, andExpr{
+			orExpr{word("fox"), word("cat")},
+			phraseExpr{terms: []string{"brown", "dog"}, offsets: []int{0, 1}},
+		}},
+		{`"lazy dog"cat`, apps.Tokenizer{}, andExpr{phraseExpr{terms: []string{"lazy", "dog"}, offsets: []int{0, 1}}, word("cat")}},
+		// A word the tokenizer splits is a phrase.
+		{"e-mail", apps.Tokenizer{}, phraseExpr{terms: []string{"e", "mail"}, offsets: []int{0, 1}}},
+		// Stop words are left out of phrases but keep their place.
+		{`"jumps over the dog"`, stopWords, phraseExpr{terms: []string{"jumps", "dog"}, offsets: []int{0, 3}}},
+		{`"the quick fox"`, stopWords, phraseExpr{terms: []string{"quick", "fox"}, offsets: []int{0, 1}}},
+		{"jumping", apps.Tokenizer{Stem: true}, word("jump")},
+	} {
+		got, err := parse(tc.query, tc.tokenizer)
+		if err != nil {
+			t.Errorf("parse(%q): %v", tc.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parse(%q) = %#v, want %#v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"fox AND",
+		"fox OR",
+		"NOT",
+		"OR fox",
+		"AND fox",
+		"(fox",
+		"fox)",
+		"()",
+		`"lazy dog`,
+		`""`,
+		"...",
+	} {
+		if e, err := parse(query, apps.Tokenizer{}); err == nil {
+			t.Errorf("parse(%q) = %#v, want an error", query, e)
+		}
+	}
+	if e, err := parse(`"the"`, apps.Tokenizer{StopWords: true}); err == nil {
+		t.Errorf("parse of a stop word alone = %#v, want an error", e)
+	}
+}
+
+// TestPhraseQueries builds positional indexes of a small corpus with a
+// positional_index job, in the text and the json_lines output format, and
+// checks the documents and lines that queries match.
+func TestPhraseQueries(t *testing.T) {
+	c := mrtest.NewCluster(t)
+	c.Start(2)
+	dir := filepath.Join(c.Dir, "corpus")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	corpus := map[string]string{
+		"a.txt": "The quick brown fox\njumps over the lazy dog\n",
+		"b.txt": "A lazy dog sleeps.\nThe brown dog barks!\n",
+		"c.txt": "dog lazy\nquick fox brown\n",
+	}
+	for name, text := range corpus {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b, c3 := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "c.txt")
+
+	for _, tc := range []struct {
+		params  map[string]string
+		format  string
+		queries map[string]result
+	}{
+		{nil, formats.Text, map[string]result{
+			`"lazy dog"`:        {a: {2: true}, b: {1: true}},
+			`"brown fox"`:       {a: {1: true}},
+			`"fox brown"`:       {c3: {2: true}},
+			`"quick brown fox"`: {a: {1: true}},
+			`"brown dog"`:       {b: {2: true}},
+			// A phrase may span lines; it matches on the line it starts on.
+			`"fox jumps"`:                {a: {1: true}},
+			`"dog lazy"`:                 {c3: {1: true}},
+			`"lazy dog" NOT barks`:       {a: {2: true}},
+			`dog NOT "lazy dog"`:         {c3: {1: true}},
+			`"brown dog" OR "fox brown"`: {b: {2: true}, c3: {2: true}},
+			`"dog barks" "lazy dog"`:     {b: {1: true, 2: true}},
+			`"lazy fox"`:                 {},
+			`"unknown words"`:            {},
+		}},
+		{map[string]string{"stopwords": "true"}, formats.JSONLines, map[string]result{
+			`"jumps over the lazy dog"`: {a: {2: true}},
+			// The stop word in the index is at a different place.
+			`"jumps the lazy dog"`:          {},
+			`"fox jumps over the lazy dog"`: {a: {1: true}},
+		}},
+	} {
+		job := c.Submit(&pb.JobSpec{InputGlob: filepath.Join(dir, "*.txt"), App: "positional_index", Params: tc.params,
+			NumReducers: 2, OutputFormat: tc.format})
+		c.Wait(job, time.Minute)
+		idx, err := loadIndex(job.OutputDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{a, b, c3}; !reflect.DeepEqual(idx.docs, want) {
+			t.Errorf("%s index has documents %v, want %v", tc.format, idx.docs, want)
+		}
+		tokenizer := apps.Tokenizer{StopWords: tc.params["stopwords"] == "true"}
+		for query, want := range tc.queries {
+			e, err := parse(query, tokenizer)
+			if err != nil {
+				t.Errorf("parse(%q): %v", query, err)
+				continue
+			}
+			if got := e.eval(idx); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s index: %s matches %v, want %v", tc.format, query, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Package mrtest runs a master and its workers in one process, on ephemeral
+// ports and in a temporary directory, so tests can run jobs end to end and
+// inject faults into them: crash a worker in the middle of a task, delay a
+// reducer, or drop an RPC. Faults are tied to RPCs rather than to timing, so
+// a test hits the same fault on every run. Sequential computes the output a
+// job must produce, however its tasks were scheduled and retried.
+package mrtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/example/master"
+	pb "github.com/example/protofiles"
+	"github.com/example/worker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Timeouts of the test master, short enough for a lost worker or attempt to
+// be noticed within a few seconds.
+const (
+	TaskTimeout      = 5 * time.Second
+	HeartbeatTimeout = 3 * time.Second
+	BackupDelay      = time.Second
+)
+
+// Cluster is a master and its workers. Fields of Master and the cluster's
+// other exported fields can be changed between NewCluster and Start.
+type Cluster struct {
+	Master     *master.Master
+	Dir        string        // temporary directory the master's and workers' files are in
+	Slowdown   time.Duration // Slowdown of every worker, to make tasks last long enough for a fault
+	TaskMemory int64         // TaskMemory of every worker if not 0
+
+	// Credentials secure the master's, the workers' and the workers' client
+	// connections if not nil. Every worker registers with Token and only
+	// maps input files inside InputRoot.
+	Credentials credentials.TransportCredentials
+	Token       string
+	InputRoot   string
+
+	// Journal is the journal the master is opened with by Start and
+	// RestartMaster, if not empty.
+	Journal string
+
+	t       testing.TB
+	addr    string
+	server  *grpc.Server
+	mu      sync.Mutex
+	workers []*Worker
+	faults  []*Fault
+}
+
+// Worker is a worker of a cluster with its gRPC server.
+type Worker struct {
+	*worker.Worker
+	Index int
+
+	server *grpc.Server
+	cancel context.CancelFunc
+	done   chan error // receives Run's result
+	once   sync.Once
+}
+
+// NewCluster creates a master with the test timeouts, working in a temporary
+// directory that is removed with the cluster when the test ends.
+func NewCluster(t testing.TB) *Cluster {
+	c := &Cluster{Master: master.New(), Dir: t.TempDir(), t: t}
+	c.Master.TaskTimeout = TaskTimeout
+	c.Master.HeartbeatTimeout = HeartbeatTimeout
+	c.Master.BackupDelay = BackupDelay
+	c.Master.WorkDir = filepath.Join(c.Dir, "jobs")
+	t.Cleanup(c.close)
+	return c
+}
+
+// Start serves the master and starts n workers. Faults should be injected
+// before, so they also apply to the first tasks.
+func (c *Cluster) Start(n int) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		c.t.Fatalf("failed to listen: %v", err)
+	}
+	c.addr = lis.Addr().String()
+	c.serveMaster(lis)
+	for i := 0; i < n; i++ {
+		c.StartWorker()
+	}
+}
+
+// serveMaster opens the master's journal, if any, and serves the master on
+// lis.
+func (c *Cluster) serveMaster(lis net.Listener) {
+	if c.Journal != "" {
+		if err := c.Master.OpenJournal(c.Journal); err != nil {
+			c.t.Fatalf("failed to open journal: %v", err)
+		}
+	}
+	c.server = grpc.NewServer(c.serverOptions()...)
+	pb.RegisterMasterServer(c.server, c.Master)
+	go c.server.Serve(lis)
+	go c.Master.Run()
+}
+
+// CrashMaster stops the master at once, as if its machine failed: RPCs to it
+// fail and it schedules nothing more, but the workers keep running and keep
+// trying to reach it.
+func (c *Cluster) CrashMaster() {
+	fmt.Printf("mrtest: crashing master (%s)\n", c.addr)
+	c.server.Stop()
+	c.Master.Shutdown()
+}
+
+// RestartMaster starts a new master, with the settings of the crashed one,
+// in its place. It recovers its jobs from the Journal, and the workers
+// register with it again once they reach it.
+func (c *Cluster) RestartMaster() {
+	old := c.Master
+	c.Master = master.New()
+	c.Master.TaskTimeout, c.Master.HeartbeatTimeout, c.Master.MaxAttempts = old.TaskTimeout, old.HeartbeatTimeout, old.MaxAttempts
+	c.Master.Speculative, c.Master.BackupDelay, c.Master.WorkDir = old.Speculative, old.BackupDelay, old.WorkDir
+	c.Master.WorkerToken, c.Master.InputRoot = old.WorkerToken, old.InputRoot
+	lis, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		c.t.Fatalf("failed to listen: %v", err)
+	}
+	c.serveMaster(lis)
+}
+
+// StartWorker starts one more worker, with its own intermediate directory.
+func (c *Cluster) StartWorker() *Worker {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		c.t.Fatalf("failed to listen: %v", err)
+	}
+	c.mu.Lock()
+	w := &Worker{Index: len(c.workers), server: grpc.NewServer(c.serverOptions()...), done: make(chan error, 1)}
+	c.workers = append(c.workers, w)
+	c.mu.Unlock()
+
+	w.Worker = worker.New(lis.Addr().String(), c.addr)
+	w.Dir = filepath.Join(c.Dir, fmt.Sprintf("worker-%d", w.Index))
+	w.Slowdown = c.Slowdown
+	if c.TaskMemory != 0 {
+		w.TaskMemory = c.TaskMemory
+	}
+	w.Credentials, w.Token, w.InputRoot = c.Credentials, c.Token, c.InputRoot
+	w.DialOptions = []grpc.DialOption{
+		grpc.WithUnaryInterceptor(c.unaryInterceptor(w)),
+		grpc.WithStreamInterceptor(c.streamInterceptor(w)),
+	}
+	pb.RegisterWorkerServer(w.server, w.Worker)
+	go w.server.Serve(lis)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	go func() { w.done <- w.Run(ctx) }()
+	return w
+}
+
+func (c *Cluster) serverOptions() []grpc.ServerOption {
+	if c.Credentials == nil {
+		return nil
+	}
+	return []grpc.ServerOption{grpc.Creds(c.Credentials)}
+}
+
+// Workers returns every worker started so far, including crashed ones.
+func (c *Cluster) Workers() []*Worker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Worker(nil), c.workers...)
+}
+
+// Crash stops a worker at once, as if its machine failed: running attempts
+// are cancelled, nothing more is reported to the master, and its
+// intermediate files can no longer be fetched. The master only learns about
+// it when the worker's heartbeats stop.
+func (w *Worker) Crash() {
+	fmt.Printf("mrtest: crashing worker %d (%s)\n", w.Index, w.Address)
+	w.stop()
+}
+
+func (w *Worker) stop() {
+	w.once.Do(func() {
+		w.cancel()
+		w.server.Stop()
+	})
+}
+
+// Submit starts a job, failing the test if the spec is rejected.
+func (c *Cluster) Submit(spec *pb.JobSpec) *master.Job {
+	j, err := c.Master.Submit(spec)
+	if err != nil {
+		c.t.Fatalf("failed to submit job: %v", err)
+	}
+	return j
+}
+
+// Wait waits up to timeout for a job to end and fails the test unless it
+// succeeded.
+func (c *Cluster) Wait(j *master.Job, timeout time.Duration) {
+	c.t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- j.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			c.t.Fatalf("job failed: %v", err)
+		}
+	case <-time.After(timeout):
+		c.t.Fatalf("job did not finish within %v", timeout)
+	}
+}
+
+// WaitJob waits up to timeout for the job with the given ID to stop running
+// and returns its status. Unlike Wait, it also works for jobs that a
+// restarted master recovered.
+func (c *Cluster) WaitJob(id string, timeout time.Duration) *pb.JobStatus {
+	c.t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		st, err := c.Master.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: id})
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		if st.State != pb.JobState_JOB_RUNNING {
+			return st
+		}
+		if time.Now().After(deadline) {
+			c.t.Fatalf("job %s did not finish within %v", id, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// SubmitPipeline starts a pipeline, failing the test if the spec is
+// rejected, and returns its ID.
+func (c *Cluster) SubmitPipeline(spec *pb.PipelineSpec) string {
+	res, err := c.Master.SubmitPipeline(context.Background(), spec)
+	if err != nil {
+		c.t.Fatalf("failed to submit pipeline: %v", err)
+	}
+	return res.PipelineId
+}
+
+// WaitPipeline waits up to timeout for a pipeline to stop running and
+// returns its status.
+func (c *Cluster) WaitPipeline(id string, timeout time.Duration) *pb.PipelineStatus {
+	c.t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		st, err := c.Master.GetPipelineStatus(context.Background(), &pb.PipelineRequest{PipelineId: id})
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		if st.State != pb.JobState_JOB_RUNNING {
+			return st
+		}
+		if time.Now().After(deadline) {
+			c.t.Fatalf("pipeline %s did not finish within %v", id, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// close tells the workers to exit, waits for them, and stops the master.
+func (c *Cluster) close() {
+	if c.server == nil {
+		return
+	}
+	c.Master.Shutdown()
+	for _, w := range c.Workers() {
+		select {
+		case <-w.done:
+		case <-time.After(10 * time.Second):
+			c.t.Errorf("worker %d did not exit", w.Index)
+		}
+		w.stop()
+	}
+	c.server.Stop()
+}
@@ -0,0 +1,172 @@
+package mrtest
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	pb "github.com/example/protofiles"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Action is what a fault does to the RPC it hits.
+type Action int
+
+const (
+	// Drop fails the RPC without sending it.
+	Drop Action = iota
+	// LoseReply sends the RPC but fails it as if the reply never arrived.
+	LoseReply
+	// Delay sends the RPC after Fault.Delay, or fails it if the caller gives
+	// up first.
+	Delay
+	// Crash crashes the worker instead of sending the RPC.
+	Crash
+	// CrashAfter sends the RPC and crashes the worker once it succeeded, e.g.
+	// right after the master committed a map task whose output only the
+	// worker holds.
+	CrashAfter
+)
+
+// AnyWorker makes a fault hit RPCs from every worker.
+const AnyWorker = -1
+
+// Fault hits RPCs that workers send to the master or, for FetchPartition, to
+// other workers. A fault only hits its first Count matching RPCs, so tests
+// know exactly what went wrong.
+type Fault struct {
+	Worker int         // index of the worker sending the RPC, or AnyWorker
+	Method string      // RPC method, e.g. "ReportTaskDone" or "FetchPartition"
+	Type   pb.TaskType // if set, only ReportTaskDone calls for tasks of this type
+	Action Action
+	Delay  time.Duration // for Delay
+	Count  int           // matching RPCs to hit, 0 for 1
+
+	mu   sync.Mutex
+	hits int
+}
+
+// Inject adds a fault to the cluster.
+func (c *Cluster) Inject(f *Fault) *Fault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faults = append(c.faults, f)
+	return f
+}
+
+// Hits returns how many RPCs the fault hit so far.
+func (f *Fault) Hits() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hits
+}
+
+// match reports whether the fault hits this RPC, and counts it if so.
+func (f *Fault) match(w *Worker, method string, req any) bool {
+	if f.Worker != AnyWorker && f.Worker != w.Index || f.Method != method {
+		return false
+	}
+	if report, ok := req.(*pb.TaskReport); f.Type != pb.TaskType_NO_TASK && (!ok || report.Type != f.Type) {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.hits >= max(f.Count, 1) {
+		return false
+	}
+	f.hits++
+	return true
+}
+
+// fault returns the first fault that hits an RPC, or nil.
+func (c *Cluster) fault(w *Worker, fullMethod string, req any) *Fault {
+	method := path.Base(fullMethod)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range c.faults {
+		if f.match(w, method, req) {
+			fmt.Printf("mrtest: worker %d: %s of %s\n", w.Index, f.Action, method)
+			return f
+		}
+	}
+	return nil
+}
+
+// before applies the part of a fault that happens before the RPC is sent. It
+// returns an error if the RPC must not be sent.
+func (f *Fault) before(ctx context.Context, w *Worker) error {
+	if f == nil {
+		return nil
+	}
+	switch f.Action {
+	case Drop:
+		return status.Errorf(codes.Unavailable, "mrtest: dropped")
+	case Crash:
+		w.Crash()
+		return status.Errorf(codes.Unavailable, "mrtest: worker crashed")
+	case Delay:
+		select {
+		case <-time.After(f.Delay):
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	return nil
+}
+
+// after applies the part of a fault that happens once the RPC returned.
+func (f *Fault) after(w *Worker, err error) error {
+	if f == nil || err != nil {
+		return err
+	}
+	switch f.Action {
+	case LoseReply:
+		return status.Errorf(codes.Unavailable, "mrtest: reply lost")
+	case CrashAfter:
+		w.Crash()
+	}
+	return nil
+}
+
+func (c *Cluster) unaryInterceptor(w *Worker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		f := c.fault(w, method, req)
+		if err := f.before(ctx, w); err != nil {
+			return err
+		}
+		return f.after(w, invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// streamInterceptor applies faults to streaming RPCs when the stream is
+// opened, before the request is sent.
+func (c *Cluster) streamInterceptor(w *Worker) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		f := c.fault(w, method, nil)
+		if err := f.before(ctx, w); err != nil {
+			return nil, err
+		}
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		return s, f.after(w, err)
+	}
+}
+
+func (a Action) String() string {
+	switch a {
+	case Drop:
+		return "drop"
+	case LoseReply:
+		return "lose reply"
+	case Delay:
+		return "delay"
+	case Crash:
+		return "crash"
+	case CrashAfter:
+		return "crash after"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
@@ -0,0 +1,942 @@
+package mrtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+	pb "github.com/example/protofiles"
+	"github.com/example/security"
+	"github.com/example/worker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// scenarios are the faults every app is run under. Each fault must hit
+// exactly Count RPCs (1 if unset) for the scenario to be meaningful.
+var scenarios = []struct {
+	name      string
+	slow      time.Duration // Slowdown of every worker
+	faults    []*Fault
+	memory    int64         // TaskMemory of every worker if not 0
+	straggler time.Duration // Slowdown of worker 0 alone if not 0; backup attempts must be launched
+}{
+	{"no faults", 0, nil, 0, 0},
+	// The smallest budget spills map output after every record, merges two
+	// files at a time and combines the values of a key as soon as there are
+	// two.
+	{"memory exhausted", 0, nil, 1, 0},
+	{"worker crashes mid-map", 0, []*Fault{
+		{Worker: AnyWorker, Method: "ReportTaskDone", Type: pb.TaskType_MAP_TASK, Action: Crash},
+	}, 0, 0},
+	{"worker crashes holding map output", 0, []*Fault{
+		{Worker: AnyWorker, Method: "ReportTaskDone", Type: pb.TaskType_MAP_TASK, Action: CrashAfter},
+	}, 0, 0},
+	{"reducer delayed", 0, []*Fault{
+		{Worker: AnyWorker, Method: "FetchPartition", Action: Delay, Delay: 3 * time.Second},
+	}, 0, 0},
+	{"map report dropped", 0, []*Fault{
+		{Worker: AnyWorker, Method: "ReportTaskDone", Type: pb.TaskType_MAP_TASK, Action: Drop},
+	}, 0, 0},
+	{"reduce report dropped", 0, []*Fault{
+		{Worker: AnyWorker, Method: "ReportTaskDone", Type: pb.TaskType_REDUCE_TASK, Action: Drop},
+	}, 0, 0},
+	{"reduce report reply lost", 0, []*Fault{
+		{Worker: AnyWorker, Method: "ReportTaskDone", Type: pb.TaskType_REDUCE_TASK, Action: LoseReply},
+	}, 0, 0},
+	{"heartbeats lost", 2 * time.Second, []*Fault{
+		// Long enough for the master to declare the worker dead while the
+		// slowed down tasks run.
+		{Worker: 0, Method: "Heartbeat", Action: Drop, Count: 4},
+	}, 0, 0},
+	// The other workers finish their own tasks long before the straggler and
+	// then run backups of its tasks, which commit first.
+	{"straggler", 0, nil, 0, 3 * time.Second},
+}
+
+func TestWordCount(t *testing.T) {
+	runScenarios(t, "word_count", nil)
+}
+
+func TestInvertedIndex(t *testing.T) {
+	runScenarios(t, "inverted_index", nil)
+}
+
+func TestGrep(t *testing.T) {
+	runScenarios(t, "grep", map[string]string{"pattern": `\bdogs?\b`, "ignore_case": "true"})
+}
+
+// TestGrepLineOrder checks that grep writes the matching lines of a file in
+// line order, line 9 before line 10, and that a job reading its input as
+// text, which would number every line 1, is rejected.
+func TestGrepLineOrder(t *testing.T) {
+	c := NewCluster(t)
+	c.Start(2)
+	input := filepath.Join(c.Dir, "input.txt")
+	var data strings.Builder
+	var want []string
+	for i := 1; i <= 12; i++ {
+		fmt.Fprintf(&data, "line %d\n", i)
+		want = append(want, fmt.Sprintf("%s:%d line %d", input, i, i))
+	}
+	if err := os.WriteFile(input, []byte(data.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	job := c.Submit(&pb.JobSpec{InputGlob: input, App: "grep", Params: map[string]string{"pattern": "line"}, NumReducers: 1})
+	c.Wait(job, time.Minute)
+	out, err := os.ReadFile(filepath.Join(job.OutputDir(), "out-0.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSuffix(string(out), "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("output is\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+
+	spec := &pb.JobSpec{InputGlob: input, App: "grep", Params: map[string]string{"pattern": "line"}, NumReducers: 1, InputFormat: formats.Text}
+	if _, err := c.Master.Submit(spec); err == nil || !strings.Contains(err.Error(), "reads its input as whole_file") {
+		t.Errorf("submitting grep over text input: got %v, want an error", err)
+	}
+}
+
+// runScenarios runs an app over the dataset on three workers under every
+// scenario and compares the output with that of Sequential.
+func runScenarios(t *testing.T, name string, params map[string]string) {
+	input, err := filepath.Abs("../dataset/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Grep keys its output by the input paths, so both runs read the same.
+	files, err := filepath.Glob(input)
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no dataset: %v", err)
+	}
+	app, err := apps.New(name, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputFormat := ""
+	if f, ok := app.(apps.InputFormatter); ok {
+		inputFormat = f.InputFormat()
+	}
+	want, err := Sequential(app, inputFormat, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			t.Parallel()
+			c := NewCluster(t)
+			var faults []*Fault
+			for _, f := range sc.faults {
+				faults = append(faults, c.Inject(&Fault{
+					Worker: f.Worker, Method: f.Method, Type: f.Type, Action: f.Action, Delay: f.Delay, Count: f.Count,
+				}))
+			}
+			c.Slowdown = sc.slow
+			c.TaskMemory = sc.memory
+			if sc.straggler > 0 {
+				c.Slowdown = sc.straggler
+			}
+			c.Start(1)
+			c.Slowdown = sc.slow
+			c.StartWorker()
+			c.StartWorker()
+			// Small splits give every worker several map tasks.
+			const reducers = 3
+			job := c.Submit(&pb.JobSpec{InputGlob: input, App: name, NumReducers: reducers, SplitSize: 64, Params: params})
+			c.Wait(job, time.Minute)
+
+			for _, f := range faults {
+				if n := f.Hits(); n != max(f.Count, 1) {
+					t.Errorf("%s of %s hit %d RPCs, want %d", f.Action, f.Method, n, max(f.Count, 1))
+				}
+			}
+			st, err := c.Master.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: job.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sc.memory != 0 && st.Counters[apps.ReduceSpilledRecords] == 0 {
+				t.Errorf("no records spilled by reducers with a memory of %d bytes", sc.memory)
+			}
+			// Pairs only written to the intermediate files are all read by a
+			// reducer; spilled runs are written on top.
+			if sc.memory != 0 && st.Counters[apps.MapSpilledRecords] <= st.Counters[apps.ReduceInputRecords] {
+				t.Errorf("no runs spilled by maps with a memory of %d bytes", sc.memory)
+			}
+			if sc.straggler > 0 && st.BackupAttempts == 0 {
+				t.Error("no backup attempts launched for the straggler")
+			}
+			// Whichever attempts ran, every reducer has exactly one committed
+			// output file.
+			outputs, err := filepath.Glob(filepath.Join(job.OutputDir(), "out-*"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var committed []string
+			for _, file := range outputs {
+				if !strings.Contains(filepath.Base(file), "-attempt-") {
+					committed = append(committed, filepath.Base(file))
+				}
+			}
+			var wantFiles []string
+			for r := 0; r < reducers; r++ {
+				wantFiles = append(wantFiles, fmt.Sprintf("out-%d.txt", r))
+			}
+			if strings.Join(committed, " ") != strings.Join(wantFiles, " ") {
+				t.Errorf("committed output files are %v, want %v", committed, wantFiles)
+			}
+			got, err := ReadOutput(job.OutputDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diffs := Diff(got, want); len(diffs) > 0 {
+				if len(diffs) > 10 {
+					diffs = append(diffs[:10], "...")
+				}
+				t.Errorf("output differs from the sequential run in %d keys:\n%s", len(diffs), strings.Join(diffs, "\n"))
+			}
+		})
+	}
+}
+
+// TestSort checks that the outputs of a range-partitioned sort, read in
+// reducer order, are the sorted input lines, also when a reducer merges on
+// disk or a worker holding map output crashes.
+func TestSort(t *testing.T) {
+	files, err := filepath.Glob("../dataset/*.txt")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no dataset: %v", err)
+	}
+	var want []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line != "" {
+				want = append(want, line)
+			}
+		}
+	}
+	sort.Strings(want)
+	input, err := filepath.Abs("../dataset/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sc := range scenarios[:4] {
+		t.Run(sc.name, func(t *testing.T) {
+			t.Parallel()
+			c := NewCluster(t)
+			for _, f := range sc.faults {
+				c.Inject(&Fault{Worker: f.Worker, Method: f.Method, Type: f.Type, Action: f.Action})
+			}
+			c.TaskMemory = sc.memory
+			c.Start(3)
+			const reducers = 3
+			job := c.Submit(&pb.JobSpec{InputGlob: input, App: "sort", NumReducers: reducers, SplitSize: 64})
+			c.Wait(job, time.Minute)
+
+			var got []string
+			for r := 0; r < reducers; r++ {
+				data, err := os.ReadFile(filepath.Join(job.OutputDir(), fmt.Sprintf("out-%d.txt", r)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+			}
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("sorted output is\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
+
+// TestHotKey sorts input in which one line makes up most of the data, many
+// times the memory budget, and checks that the maps spill their output and
+// the reducer getting it streams its values instead of holding them, with
+// and without a sort field.
+func TestHotKey(t *testing.T) {
+	const (
+		budget = 1 << 20
+		copies = 40000
+	)
+	hot := "the hot line " + strings.Repeat("x", 100)
+	var data strings.Builder
+	for i := 0; i < copies; i++ {
+		data.WriteString(hot + "\n")
+		if i%1000 == 0 {
+			fmt.Fprintf(&data, "line %05d\n", i)
+		}
+	}
+	want := strings.Split(strings.TrimSuffix(data.String(), "\n"), "\n")
+	sort.Strings(want)
+
+	for _, params := range []map[string]string{nil, {"field": "2"}} {
+		t.Run(fmt.Sprintf("params %v", params), func(t *testing.T) {
+			t.Parallel()
+			c := NewCluster(t)
+			c.TaskMemory = budget
+			c.Start(3)
+			input := filepath.Join(c.Dir, "hot.txt")
+			if err := os.WriteFile(input, []byte(data.String()), 0644); err != nil {
+				t.Fatal(err)
+			}
+			const reducers = 2
+			job := c.Submit(&pb.JobSpec{InputGlob: input, App: "sort", NumReducers: reducers, SplitSize: 2 << 20, Params: params})
+			c.Wait(job, time.Minute)
+
+			st, err := c.Master.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: job.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, task := range st.Tasks {
+				if task.BufferedBytesPeak > budget {
+					t.Errorf("%s %d held %d bytes, over its budget of %d", task.Type, task.TaskId, task.BufferedBytesPeak, budget)
+				}
+			}
+			if st.Counters[apps.MapSpilledRecords] <= st.Counters[apps.ReduceInputRecords] {
+				t.Error("no runs spilled by maps")
+			}
+			var got []string
+			for r := 0; r < reducers; r++ {
+				data, err := os.ReadFile(filepath.Join(job.OutputDir(), fmt.Sprintf("out-%d.txt", r)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(data) > 0 {
+					got = append(got, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+				}
+			}
+			// Numbers sort before "hot", so both orders are that of the lines.
+			if !sort.StringsAreSorted(got) {
+				t.Error("output is not sorted")
+			}
+			sort.Strings(got)
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("output has %d lines, want the %d input lines", len(got), len(want))
+			}
+		})
+	}
+}
+
+// TestCompression runs word_count with its intermediate files and output
+// compressed with every codec, and checks the output and the compression
+// statistics of every task.
+func TestCompression(t *testing.T) {
+	input, err := filepath.Abs("../dataset/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(input)
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no dataset: %v", err)
+	}
+	app, err := apps.New("word_count", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Sequential(app, "", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, codec := range []string{formats.Gzip, formats.Zstd, formats.Snappy} {
+		t.Run(codec, func(t *testing.T) {
+			t.Parallel()
+			c := NewCluster(t)
+			c.Start(3)
+			job := c.Submit(&pb.JobSpec{InputGlob: input, App: "word_count", NumReducers: 3, SplitSize: 64,
+				IntermediateCodec: codec, OutputFormat: formats.Text + "+" + codec})
+			c.Wait(job, time.Minute)
+
+			st, err := c.Master.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: job.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, task := range st.Tasks {
+				if task.CompressedBytes == 0 || task.UncompressedBytes == 0 || task.CompressionRatio == 0 {
+					t.Errorf("%s task %d has no compression statistics: %v", task.Type, task.TaskId, task)
+				}
+			}
+			got, err := ReadOutput(job.OutputDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) == 0 {
+				t.Fatal("no output")
+			}
+			if diffs := Diff(got, want); len(diffs) > 0 {
+				t.Errorf("output differs from the sequential run in %d keys:\n%s", len(diffs), strings.Join(diffs, "\n"))
+			}
+		})
+	}
+}
+
+// TestJSONLinesOutput checks that json_lines output embeds the values of apps
+// that declare them JSON and writes all others as strings, even lines that
+// grep matched which happen to be valid JSON.
+func TestJSONLinesOutput(t *testing.T) {
+	c := NewCluster(t)
+	c.Start(2)
+	input := filepath.Join(c.Dir, "input.txt")
+	if err := os.WriteFile(input, []byte("42\ntrue\nnull\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		app    string
+		params map[string]string
+		want   map[string]any
+	}{
+		{"grep", map[string]string{"pattern": "."}, map[string]any{
+			input + ":1": "42", input + ":2": "true", input + ":3": "null",
+		}},
+		{"word_count", nil, map[string]any{"42": 1.0, "true": 1.0, "null": 1.0}},
+	} {
+		job := c.Submit(&pb.JobSpec{InputGlob: input, App: tc.app, Params: tc.params, NumReducers: 1, OutputFormat: formats.JSONLines})
+		c.Wait(job, time.Minute)
+		data, err := os.ReadFile(filepath.Join(job.OutputDir(), "out-0.jsonl"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]any)
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			var kv struct {
+				Key   string `json:"key"`
+				Value any    `json:"value"`
+			}
+			if err := json.Unmarshal([]byte(line), &kv); err != nil {
+				t.Fatalf("%s: bad output line %q: %v", tc.app, line, err)
+			}
+			got[kv.Key] = kv.Value
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: output is %v, want %v", tc.app, got, tc.want)
+		}
+	}
+}
+
+// TestIncremental runs inverted_index incrementally over a copy of the
+// dataset as it changes, and checks that only the map tasks of changed files
+// and the reduce tasks of the partitions they touch run again, also when the
+// worker holding reused map output has crashed.
+func TestIncremental(t *testing.T) {
+	c := NewCluster(t)
+	c.Start(3)
+	dir := filepath.Join(c.Dir, "input")
+	files := copyDataset(t, dir)
+	app, err := apps.New("inverted_index", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const reducers = 8
+	input := filepath.Join(dir, "*.txt")
+
+	// run runs the job and checks its output and how many map and reduce
+	// tasks were reused; -1 accepts any number.
+	run := func(step string, reusedMaps, reusedReduces int) *pb.JobStatus {
+		t.Helper()
+		job := c.Submit(&pb.JobSpec{InputGlob: input, App: "inverted_index", NumReducers: reducers, SplitSize: 64, Incremental: true})
+		c.Wait(job, time.Minute)
+		st, err := c.Master.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: job.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := int(st.Map.Reused); reusedMaps >= 0 && n != reusedMaps {
+			t.Errorf("%s: reused %d of %d map tasks, want %d", step, n, st.Map.Total, reusedMaps)
+		}
+		if n := int(st.Reduce.Reused); reusedReduces >= 0 && n != reusedReduces {
+			t.Errorf("%s: reused %d of %d reduce tasks, want %d", step, n, st.Reduce.Total, reusedReduces)
+		}
+		files, _ := filepath.Glob(input)
+		want, err := Sequential(app, "", files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadOutput(job.OutputDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diffs := Diff(got, want); len(diffs) > 0 {
+			t.Errorf("%s: output differs from the sequential run in %d keys:\n%s", step, len(diffs), strings.Join(diffs, "\n"))
+		}
+		return st
+	}
+
+	first := run("first run", 0, 0)
+	run("unchanged input", int(first.Map.Total), reducers)
+
+	// A new file with a single word touches a single partition.
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("zebra\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("file added", int(first.Map.Total), reducers-1)
+
+	// Reused map output on a crashed worker is mapped again.
+	c.Workers()[0].Crash()
+	c.StartWorker()
+	f, err := os.OpenFile(filepath.Join(dir, filepath.Base(files[0])), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintln(f, "the quick zebra")
+	f.Close()
+	run("file changed and worker crashed", -1, -1)
+}
+
+// copyDataset copies the dataset into dir, for tests that change their input,
+// and returns the names of the copies.
+func copyDataset(t *testing.T, dir string) []string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob("../dataset/*.txt")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no dataset: %v", err)
+	}
+	var copies []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := filepath.Join(dir, filepath.Base(file))
+		if err := os.WriteFile(name, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		copies = append(copies, name)
+	}
+	return copies
+}
+
+// TestMasterRestart crashes the master once some reduce tasks of a job have
+// completed, deletes the output of one of them, and checks that a master
+// restarted against the same journal runs that task again and finishes the
+// job with the output of the sequential run.
+func TestMasterRestart(t *testing.T) {
+	input, err := filepath.Abs("../dataset/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(input)
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no dataset: %v", err)
+	}
+	app, err := apps.New("word_count", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Sequential(app, "", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCluster(t)
+	c.Journal = filepath.Join(c.Dir, "journal")
+	c.Slowdown = 500 * time.Millisecond
+	c.Start(3)
+	job := c.Submit(&pb.JobSpec{InputGlob: input, App: "word_count", NumReducers: 8, SplitSize: 64})
+	var deleted int32 = -1
+	for deadline := time.Now().Add(time.Minute); deleted < 0; time.Sleep(50 * time.Millisecond) {
+		st, err := c.Master.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: job.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st.State != pb.JobState_JOB_RUNNING {
+			t.Fatalf("job ended before the master crashed: %s", st.State)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no reduce task completed within a minute")
+		}
+		if st.Reduce.Completed == 0 {
+			continue
+		}
+		c.CrashMaster()
+		for _, task := range st.Tasks {
+			if task.Type == pb.TaskType_REDUCE_TASK {
+				deleted = task.TaskId
+			}
+		}
+	}
+	out := filepath.Join(job.OutputDir(), fmt.Sprintf("out-%d.txt", deleted))
+	if err := os.Remove(out); err != nil {
+		t.Fatal(err)
+	}
+
+	c.RestartMaster()
+	st := c.WaitJob(job.ID, time.Minute)
+	if st.State != pb.JobState_JOB_SUCCEEDED {
+		t.Fatalf("recovered job %s: %s", st.State, st.Error)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Errorf("deleted output of reduce task %d was not written again: %v", deleted, err)
+	}
+	got, err := ReadOutput(st.OutputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := Diff(got, want); len(diffs) > 0 {
+		t.Errorf("output differs from the sequential run in %d keys:\n%s", len(diffs), strings.Join(diffs, "\n"))
+	}
+}
+
+// TestSecurity runs a job on a cluster with mutual TLS, a registration token
+// and an input root, and checks that peers without a certificate of the
+// cluster's CA, workers without the token, and files and directories outside
+// the root, whether reached through ".." or a symbolic link, are all turned
+// away, as are map and reduce requests for partitions out of range.
+func TestSecurity(t *testing.T) {
+	c := NewCluster(t)
+	certs, err := GenerateCerts(filepath.Join(c.Dir, "certs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds, err := security.Credentials(certs.CA, certs.Cert, certs.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Join(c.Dir, "root")
+	input := filepath.Join(root, "input")
+	files := copyDataset(t, input)
+	secret := filepath.Join(c.Dir, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(input, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	c.Credentials, c.Token, c.InputRoot = creds, "token", root
+	c.Master.WorkerToken, c.Master.InputRoot = "token", root
+	c.Master.WorkDir = filepath.Join(root, "jobs")
+	c.Start(2)
+
+	job := c.Submit(&pb.JobSpec{InputGlob: filepath.Join(input, "file*.txt"), App: "word_count", NumReducers: 2})
+	c.Wait(job, time.Minute)
+	app, err := apps.New("word_count", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Sequential(app, "", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadOutput(job.OutputDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := Diff(got, want); len(diffs) > 0 {
+		t.Errorf("output differs from the sequential run in %d keys:\n%s", len(diffs), strings.Join(diffs, "\n"))
+	}
+
+	for _, spec := range []*pb.JobSpec{
+		{InputGlob: filepath.Join(c.Dir, "*.txt")},
+		{InputGlob: input + "/../../*.txt"},
+		{InputGlob: filepath.Join(input, "*.txt")},
+		{InputGlob: filepath.Join(input, "file*.txt"), OutputDir: filepath.Join(c.Dir, "output")},
+	} {
+		spec.App, spec.NumReducers = "word_count", 1
+		if _, err := c.Master.Submit(spec); err == nil || !strings.Contains(err.Error(), "is outside") {
+			t.Errorf("submitting a job over %s with output in %q: got %v, want an error", spec.InputGlob, spec.OutputDir, err)
+		}
+	}
+
+	w := worker.New("127.0.0.1:1", c.addr)
+	w.Credentials, w.Token = creds, "wrong"
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := w.Run(ctx); err == nil || !strings.Contains(err.Error(), "invalid registration token") {
+		t.Errorf("worker with the wrong token: got %v, want a rejected registration", err)
+	}
+
+	// call calls a worker's service with the given credentials.
+	addr := c.Workers()[0].Address
+	call := func(creds credentials.TransportCredentials, f func(ctx context.Context, client pb.WorkerClient) error) error {
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return f(ctx, pb.NewWorkerClient(conn))
+	}
+	mapFile := func(creds credentials.TransportCredentials, filename, dir string) error {
+		return call(creds, func(ctx context.Context, client pb.WorkerClient) error {
+			_, err := client.Map(ctx, &pb.MapRequest{
+				JobId:           "probe",
+				Filename:        filename,
+				NumReducers:     1,
+				Mode:            "word_count",
+				Attempt:         1,
+				IntermediateDir: dir,
+			})
+			return err
+		})
+	}
+	if err := mapFile(creds, files[0], "probe"); err != nil {
+		t.Errorf("mapping %s: %v", files[0], err)
+	}
+	other, err := GenerateCerts(filepath.Join(c.Dir, "other-certs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCreds, err := security.Credentials(other.CA, other.Cert, other.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, creds := range map[string]credentials.TransportCredentials{
+		"without TLS":                      insecure.NewCredentials(),
+		"with a certificate of another CA": otherCreds,
+	} {
+		if err := mapFile(creds, files[0], "probe"); err == nil {
+			t.Errorf("a client %s could call Map", name)
+		}
+	}
+	for _, name := range []string{secret, input + "/../../secret.txt", filepath.Join(input, "link.txt"), "/etc/passwd"} {
+		if err := mapFile(creds, name, "probe"); err == nil || !strings.Contains(err.Error(), "is outside") {
+			t.Errorf("mapping %s: got %v, want it to be outside the root", name, err)
+		}
+	}
+	if err := mapFile(creds, files[0], "../../escape"); err == nil || !strings.Contains(err.Error(), "is outside") {
+		t.Errorf("mapping into ../../escape: got %v, want it to be outside the worker's directory", err)
+	}
+	err = call(creds, func(ctx context.Context, client pb.WorkerClient) error {
+		_, err := client.Reduce(ctx, &pb.ReduceRequest{
+			JobId:           "probe",
+			Mode:            "word_count",
+			Attempt:         1,
+			IntermediateDir: "probe",
+			OutputDir:       filepath.Join(c.Dir, "output"),
+		})
+		return err
+	})
+	if err == nil || !strings.Contains(err.Error(), "is outside") {
+		t.Errorf("reducing into %s: got %v, want it to be outside the root", filepath.Join(c.Dir, "output"), err)
+	}
+	for _, n := range []int32{0, -1, apps.MaxReducers + 1, 1 << 30} {
+		err = call(creds, func(ctx context.Context, client pb.WorkerClient) error {
+			_, err := client.Map(ctx, &pb.MapRequest{JobId: "probe", Filename: files[0], NumReducers: n, Mode: "word_count", Attempt: 1, IntermediateDir: "probe"})
+			return err
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("mapping into %d partitions: got %v, want InvalidArgument", n, err)
+		}
+	}
+	for _, id := range []int32{-1, apps.MaxReducers, 1 << 30} {
+		err = call(creds, func(ctx context.Context, client pb.WorkerClient) error {
+			_, err := client.Reduce(ctx, &pb.ReduceRequest{JobId: "probe", ReduceTaskId: id, Mode: "word_count", Attempt: 1, IntermediateDir: "probe"})
+			return err
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("reducing partition %d: got %v, want InvalidArgument", id, err)
+		}
+	}
+	err = call(creds, func(ctx context.Context, client pb.WorkerClient) error {
+		stream, err := client.FetchPartition(ctx, &pb.FetchPartitionRequest{JobId: "probe", IntermediateDir: "../..", Attempt: 1})
+		if err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("fetching a partition from ../..: got %v, want PermissionDenied", err)
+	}
+}
+
+// TestReportedOutputFile checks that the master only commits a reduce
+// attempt's output file under the name it expects, in the job's output
+// directory, whatever file a worker reports.
+func TestReportedOutputFile(t *testing.T) {
+	c := NewCluster(t)
+	c.Start(0)
+	victim := filepath.Join(c.Dir, "victim.txt")
+	if err := os.WriteFile(victim, []byte("victim\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	job := c.Submit(&pb.JobSpec{InputGlob: "../dataset/file1.txt", App: "word_count", NumReducers: 1})
+
+	// A worker that reports its map task done, then its reduce task done
+	// with another file as its output.
+	ctx := context.Background()
+	reg, err := c.Master.RegisterWorker(ctx, &pb.RegisterWorkerRequest{Address: "127.0.0.1:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, outputFile := range []string{"", victim} {
+		var a *pb.TaskAssignment
+		for a == nil || a.Type == pb.TaskType_NO_TASK {
+			if a, err = c.Master.RequestTask(ctx, &pb.TaskRequest{WorkerId: reg.WorkerId, FreeMapSlots: 1, FreeReduceSlots: 1}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		report := &pb.TaskReport{WorkerId: reg.WorkerId, JobId: a.JobId, Type: a.Type, Attempt: a.Attempt, Result: &pb.TaskResponse{Success: true}}
+		if a.Type == pb.TaskType_REDUCE_TASK {
+			report.TaskId, report.Result.OutputFile = a.Reduce.ReduceTaskId, outputFile
+		} else {
+			report.TaskId = a.Map.MapTaskId
+		}
+		if _, err := c.Master.ReportTaskDone(ctx, report); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Errorf("%s was moved: %v", victim, err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(job.OutputDir(), "out-*")); len(files) > 0 {
+		t.Errorf("committed %v", files)
+	}
+	st, err := c.Master.GetJobStatus(ctx, &pb.JobStatusRequest{JobId: job.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Reduce.Completed != 0 {
+		t.Errorf("%d reduce tasks completed, want none", st.Reduce.Completed)
+	}
+}
+
+// TestPipeline runs word_count followed by top_k and checks that the second
+// stage only reads the first stage's committed output, not the file of a
+// reduce attempt that was left behind.
+func TestPipeline(t *testing.T) {
+	const input = "../dataset/*.txt"
+	c := NewCluster(t)
+	c.Start(3)
+	counts := filepath.Join(c.Dir, "counts")
+	if err := os.MkdirAll(counts, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// As if a worker died between writing its output and reporting it.
+	if err := os.WriteFile(filepath.Join(counts, "out-0-attempt-7.txt"), []byte("stray 1000000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	id := c.SubmitPipeline(&pb.PipelineSpec{Stages: []*pb.JobSpec{
+		{InputGlob: input, App: "word_count", NumReducers: 3, SplitSize: 64, OutputDir: counts},
+		{App: "top_k", NumReducers: 1, Params: map[string]string{"k": "5"}},
+	}})
+	st := c.WaitPipeline(id, time.Minute)
+	if st.State != pb.JobState_JOB_SUCCEEDED {
+		t.Fatalf("pipeline %s: %s", st.State, st.Error)
+	}
+
+	files, _ := filepath.Glob(input)
+	want := sequentialStages(t, files, []string{"word_count", "top_k"}, []map[string]string{nil, {"k": "5"}})
+	got, err := ReadOutput(st.Stages[1].OutputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := Diff(got, want); len(diffs) > 0 {
+		t.Errorf("output differs from the sequential run in %d keys:\n%s", len(diffs), strings.Join(diffs, "\n"))
+	}
+}
+
+// TestPipelineRestart runs word_count with gzip-compressed output followed by
+// top_k, which must read it in that format, cancels the pipeline while its
+// second stage runs, and checks that a restart runs only the second stage
+// again, to the output of the sequential run.
+func TestPipelineRestart(t *testing.T) {
+	const input = "../dataset/*.txt"
+	c := NewCluster(t)
+	c.Slowdown = 500 * time.Millisecond
+	c.Start(3)
+	ctx := context.Background()
+	id := c.SubmitPipeline(&pb.PipelineSpec{Stages: []*pb.JobSpec{
+		{InputGlob: input, App: "word_count", NumReducers: 3, OutputFormat: formats.Text + "+" + formats.Gzip},
+		{App: "top_k", NumReducers: 1, Params: map[string]string{"k": "5"}},
+	}})
+
+	var cancelled *pb.PipelineStatus
+	for deadline := time.Now().Add(time.Minute); cancelled == nil; time.Sleep(20 * time.Millisecond) {
+		st, err := c.Master.GetPipelineStatus(ctx, &pb.PipelineRequest{PipelineId: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st.State != pb.JobState_JOB_RUNNING {
+			t.Fatalf("pipeline %s before it was cancelled: %s", st.State, st.Error)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stage 2 did not start within a minute")
+		}
+		if len(st.Stages) == 2 && st.Stages[1].State == pb.JobState_JOB_RUNNING {
+			if cancelled, err = c.Master.CancelPipeline(ctx, &pb.PipelineRequest{PipelineId: id}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if cancelled.State != pb.JobState_JOB_CANCELLED {
+		t.Fatalf("cancelled pipeline is %s", cancelled.State)
+	}
+	if _, err := c.Master.RestartPipeline(ctx, &pb.PipelineRequest{PipelineId: id}); err != nil {
+		t.Fatal(err)
+	}
+	st := c.WaitPipeline(id, time.Minute)
+	if st.State != pb.JobState_JOB_SUCCEEDED {
+		t.Fatalf("restarted pipeline %s: %s", st.State, st.Error)
+	}
+	if len(st.Stages) != 2 {
+		t.Fatalf("pipeline has %d stages, want 2", len(st.Stages))
+	}
+	if first := cancelled.Stages[0].JobId; st.Stages[0].JobId != first {
+		t.Errorf("stage 1 ran again as %s, want it kept as %s", st.Stages[0].JobId, first)
+	}
+	if second := cancelled.Stages[1].JobId; st.Stages[1].JobId == second {
+		t.Errorf("stage 2 was not restarted, still %s", second)
+	}
+	if format := st.Stages[1].Spec.InputFormat; format != formats.Text+"+"+formats.Gzip {
+		t.Errorf("stage 2 reads %q, want the output format of stage 1", format)
+	}
+
+	files, _ := filepath.Glob(input)
+	want := sequentialStages(t, files, []string{"word_count", "top_k"}, []map[string]string{nil, {"k": "5"}})
+	got, err := ReadOutput(st.Stages[1].OutputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := Diff(got, want); len(diffs) > 0 {
+		t.Errorf("output differs from the sequential run in %d keys:\n%s", len(diffs), strings.Join(diffs, "\n"))
+	}
+}
+
+// sequentialStages runs apps one after the other with Sequential, each over
+// the output of the one before written in the text format, and returns the
+// output of the last.
+func sequentialStages(t *testing.T, files []string, names []string, params []map[string]string) map[string]string {
+	t.Helper()
+	var out map[string]string
+	for i, name := range names {
+		app, err := apps.New(name, params[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out, err = Sequential(app, "", files); err != nil {
+			t.Fatal(err)
+		}
+		var lines []string
+		for key, value := range out {
+			lines = append(lines, key+" "+value+"\n")
+		}
+		sort.Strings(lines)
+		file := filepath.Join(t.TempDir(), fmt.Sprintf("stage-%d.txt", i+1))
+		if err := os.WriteFile(file, []byte(strings.Join(lines, "")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = []string{file}
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+package mrtest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+)
+
+// Sequential runs an app over files in one pass, the way a single worker
+// would without splits, partitions or a combiner: every record of every file
+// is mapped, the values are grouped by key and every key is reduced once.
+// It returns the reduce result of every key, or the pairs written by the
+// reducer of an apps.StreamReducer or apps.RecordReducer.
+func Sequential(app apps.MapReduceApp, inputFormat string, files []string) (map[string]string, error) {
+	format, err := formats.LookupInput(inputFormat)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string][]string)
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		err = format.ReadRecords(f, func(record string) error {
+			for _, kv := range app.Map(file, record) {
+				groups[kv.Key] = append(groups[kv.Key], kv.Value)
+			}
+			return nil
+		})
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file, err)
+		}
+	}
+	out := make(map[string]string, len(groups))
+	for key, values := range groups {
+		switch r := app.(type) {
+		case apps.StreamReducer:
+			r.ReduceStream(key, slices.Values(values), func(kv apps.KeyValue) { out[kv.Key] = kv.Value })
+		case apps.RecordReducer:
+			for _, kv := range r.ReduceRecords(key, values) {
+				out[kv.Key] = kv.Value
+			}
+		default:
+			out[key] = app.Reduce(key, values)
+		}
+	}
+	return out, nil
+}
+
+// ReadOutput reads the out-*.txt files of a job written in the text output
+// format, compressed or not, skipping reduce attempts that were never
+// committed. It fails if a key appears twice.
+func ReadOutput(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "out-*.txt*"))
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string)
+	for _, file := range files {
+		if strings.Contains(filepath.Base(file), "-attempt-") {
+			continue
+		}
+		if err := readOutputFile(file, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func readOutputFile(file string, out map[string]string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, codec := formats.CutCodecExt(file)
+	zr, err := codec.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	defer zr.Close()
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		if _, dup := out[key]; dup {
+			return fmt.Errorf("key %q written twice, again in %s", key, file)
+		}
+		out[key] = value
+	}
+	return scanner.Err()
+}
+
+// Diff lists the keys whose value differs between got and want, in key
+// order.
+func Diff(got, want map[string]string) []string {
+	var diffs []string
+	for key, w := range want {
+		if g, ok := got[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%q: missing, want %q", key, w))
+		} else if g != w {
+			diffs = append(diffs, fmt.Sprintf("%q: got %q, want %q", key, g, w))
+		}
+	}
+	for key, g := range got {
+		if _, ok := want[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%q: got %q, not expected", key, g))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
@@ -0,0 +1,50 @@
+// Command ngram is an example MapReduce app built as a Go plugin:
+//
+//	go build -buildmode=plugin -o plugins/ngram.so ./plugins/ngram
+//
+// It counts word bigrams and is loaded by workers with -plugin plugins/ngram.so.
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/example/apps"
+)
+
+type bigrams struct{}
+
+func (bigrams) Map(filename string, contents string) []apps.KeyValue {
+	var kvs []apps.KeyValue
+	words := strings.Fields(strings.ToLower(contents))
+	for i := 0; i+1 < len(words); i++ {
+		kvs = append(kvs, apps.KeyValue{Key: words[i] + "_" + words[i+1], Value: "1"})
+	}
+	return kvs
+}
+
+func (bigrams) Reduce(key string, values []string) string {
+	total := 0
+	for _, v := range values {
+		n, _ := strconv.Atoi(v)
+		total += n
+	}
+	return strconv.Itoa(total)
+}
+
+// Combine makes the plugin an apps.Combiner, so counts are summed map-side.
+func (b bigrams) Combine(key string, values []string) []string {
+	return []string{b.Reduce(key, values)}
+}
+
+// JSONValues makes the plugin an apps.JSONValuer, so json_lines output holds
+// the counts as numbers.
+func (bigrams) JSONValues() bool { return true }
+
+// Name is the app name the plugin registers under.
+var Name = "ngram"
+
+// App is looked up by apps.LoadPlugin.
+var App apps.MapReduceApp = bigrams{}
+
+func main() {}
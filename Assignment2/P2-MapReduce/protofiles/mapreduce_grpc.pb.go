@@ -19,16 +19,36 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Master_AssignMapTask_FullMethodName    = "/protofiles.Master/AssignMapTask"
-	Master_AssignReduceTask_FullMethodName = "/protofiles.Master/AssignReduceTask"
+	Master_RegisterWorker_FullMethodName    = "/protofiles.Master/RegisterWorker"
+	Master_RequestTask_FullMethodName       = "/protofiles.Master/RequestTask"
+	Master_ReportTaskDone_FullMethodName    = "/protofiles.Master/ReportTaskDone"
+	Master_Heartbeat_FullMethodName         = "/protofiles.Master/Heartbeat"
+	Master_SubmitJob_FullMethodName         = "/protofiles.Master/SubmitJob"
+	Master_GetJobStatus_FullMethodName      = "/protofiles.Master/GetJobStatus"
+	Master_CancelJob_FullMethodName         = "/protofiles.Master/CancelJob"
+	Master_SubmitPipeline_FullMethodName    = "/protofiles.Master/SubmitPipeline"
+	Master_GetPipelineStatus_FullMethodName = "/protofiles.Master/GetPipelineStatus"
+	Master_CancelPipeline_FullMethodName    = "/protofiles.Master/CancelPipeline"
+	Master_RestartPipeline_FullMethodName   = "/protofiles.Master/RestartPipeline"
 )
 
 // MasterClient is the client API for Master service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MasterClient interface {
-	AssignMapTask(ctx context.Context, in *MapRequest, opts ...grpc.CallOption) (*TaskResponse, error)
-	AssignReduceTask(ctx context.Context, in *ReduceRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error)
+	RequestTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*TaskAssignment, error)
+	ReportTaskDone(ctx context.Context, in *TaskReport, opts ...grpc.CallOption) (*TaskReportAck, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	SubmitJob(ctx context.Context, in *JobSpec, opts ...grpc.CallOption) (*SubmitJobResponse, error)
+	GetJobStatus(ctx context.Context, in *JobStatusRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	SubmitPipeline(ctx context.Context, in *PipelineSpec, opts ...grpc.CallOption) (*SubmitPipelineResponse, error)
+	GetPipelineStatus(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelineStatus, error)
+	CancelPipeline(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelineStatus, error)
+	// RestartPipeline runs a failed or cancelled pipeline again from its first
+	// stage that did not succeed.
+	RestartPipeline(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelineStatus, error)
 }
 
 type masterClient struct {
@@ -39,20 +59,110 @@ func NewMasterClient(cc grpc.ClientConnInterface) MasterClient {
 	return &masterClient{cc}
 }
 
-func (c *masterClient) AssignMapTask(ctx context.Context, in *MapRequest, opts ...grpc.CallOption) (*TaskResponse, error) {
+func (c *masterClient) RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(TaskResponse)
-	err := c.cc.Invoke(ctx, Master_AssignMapTask_FullMethodName, in, out, cOpts...)
+	out := new(RegisterWorkerResponse)
+	err := c.cc.Invoke(ctx, Master_RegisterWorker_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *masterClient) AssignReduceTask(ctx context.Context, in *ReduceRequest, opts ...grpc.CallOption) (*TaskResponse, error) {
+func (c *masterClient) RequestTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*TaskAssignment, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(TaskResponse)
-	err := c.cc.Invoke(ctx, Master_AssignReduceTask_FullMethodName, in, out, cOpts...)
+	out := new(TaskAssignment)
+	err := c.cc.Invoke(ctx, Master_RequestTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) ReportTaskDone(ctx context.Context, in *TaskReport, opts ...grpc.CallOption) (*TaskReportAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskReportAck)
+	err := c.cc.Invoke(ctx, Master_ReportTaskDone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, Master_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) SubmitJob(ctx context.Context, in *JobSpec, opts ...grpc.CallOption) (*SubmitJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitJobResponse)
+	err := c.cc.Invoke(ctx, Master_SubmitJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) GetJobStatus(ctx context.Context, in *JobStatusRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, Master_GetJobStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, Master_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) SubmitPipeline(ctx context.Context, in *PipelineSpec, opts ...grpc.CallOption) (*SubmitPipelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitPipelineResponse)
+	err := c.cc.Invoke(ctx, Master_SubmitPipeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) GetPipelineStatus(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelineStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PipelineStatus)
+	err := c.cc.Invoke(ctx, Master_GetPipelineStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) CancelPipeline(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelineStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PipelineStatus)
+	err := c.cc.Invoke(ctx, Master_CancelPipeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterClient) RestartPipeline(ctx context.Context, in *PipelineRequest, opts ...grpc.CallOption) (*PipelineStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PipelineStatus)
+	err := c.cc.Invoke(ctx, Master_RestartPipeline_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -63,8 +173,19 @@ func (c *masterClient) AssignReduceTask(ctx context.Context, in *ReduceRequest,
 // All implementations must embed UnimplementedMasterServer
 // for forward compatibility.
 type MasterServer interface {
-	AssignMapTask(context.Context, *MapRequest) (*TaskResponse, error)
-	AssignReduceTask(context.Context, *ReduceRequest) (*TaskResponse, error)
+	RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error)
+	RequestTask(context.Context, *TaskRequest) (*TaskAssignment, error)
+	ReportTaskDone(context.Context, *TaskReport) (*TaskReportAck, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	SubmitJob(context.Context, *JobSpec) (*SubmitJobResponse, error)
+	GetJobStatus(context.Context, *JobStatusRequest) (*JobStatus, error)
+	CancelJob(context.Context, *CancelJobRequest) (*JobStatus, error)
+	SubmitPipeline(context.Context, *PipelineSpec) (*SubmitPipelineResponse, error)
+	GetPipelineStatus(context.Context, *PipelineRequest) (*PipelineStatus, error)
+	CancelPipeline(context.Context, *PipelineRequest) (*PipelineStatus, error)
+	// RestartPipeline runs a failed or cancelled pipeline again from its first
+	// stage that did not succeed.
+	RestartPipeline(context.Context, *PipelineRequest) (*PipelineStatus, error)
 	mustEmbedUnimplementedMasterServer()
 }
 
@@ -75,11 +196,38 @@ type MasterServer interface {
 // pointer dereference when methods are called.
 type UnimplementedMasterServer struct{}
 
-func (UnimplementedMasterServer) AssignMapTask(context.Context, *MapRequest) (*TaskResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AssignMapTask not implemented")
+func (UnimplementedMasterServer) RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWorker not implemented")
+}
+func (UnimplementedMasterServer) RequestTask(context.Context, *TaskRequest) (*TaskAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestTask not implemented")
+}
+func (UnimplementedMasterServer) ReportTaskDone(context.Context, *TaskReport) (*TaskReportAck, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportTaskDone not implemented")
+}
+func (UnimplementedMasterServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedMasterServer) SubmitJob(context.Context, *JobSpec) (*SubmitJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (UnimplementedMasterServer) GetJobStatus(context.Context, *JobStatusRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
+}
+func (UnimplementedMasterServer) CancelJob(context.Context, *CancelJobRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedMasterServer) SubmitPipeline(context.Context, *PipelineSpec) (*SubmitPipelineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitPipeline not implemented")
+}
+func (UnimplementedMasterServer) GetPipelineStatus(context.Context, *PipelineRequest) (*PipelineStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPipelineStatus not implemented")
 }
-func (UnimplementedMasterServer) AssignReduceTask(context.Context, *ReduceRequest) (*TaskResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AssignReduceTask not implemented")
+func (UnimplementedMasterServer) CancelPipeline(context.Context, *PipelineRequest) (*PipelineStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelPipeline not implemented")
+}
+func (UnimplementedMasterServer) RestartPipeline(context.Context, *PipelineRequest) (*PipelineStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartPipeline not implemented")
 }
 func (UnimplementedMasterServer) mustEmbedUnimplementedMasterServer() {}
 func (UnimplementedMasterServer) testEmbeddedByValue()                {}
@@ -102,38 +250,200 @@ func RegisterMasterServer(s grpc.ServiceRegistrar, srv MasterServer) {
 	s.RegisterService(&Master_ServiceDesc, srv)
 }
 
-func _Master_AssignMapTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MapRequest)
+func _Master_RegisterWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWorkerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MasterServer).AssignMapTask(ctx, in)
+		return srv.(MasterServer).RegisterWorker(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Master_AssignMapTask_FullMethodName,
+		FullMethod: Master_RegisterWorker_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).AssignMapTask(ctx, req.(*MapRequest))
+		return srv.(MasterServer).RegisterWorker(ctx, req.(*RegisterWorkerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Master_AssignReduceTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReduceRequest)
+func _Master_RequestTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).RequestTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_RequestTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).RequestTask(ctx, req.(*TaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_ReportTaskDone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskReport)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).ReportTaskDone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_ReportTaskDone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).ReportTaskDone(ctx, req.(*TaskReport))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MasterServer).AssignReduceTask(ctx, in)
+		return srv.(MasterServer).Heartbeat(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Master_AssignReduceTask_FullMethodName,
+		FullMethod: Master_Heartbeat_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MasterServer).AssignReduceTask(ctx, req.(*ReduceRequest))
+		return srv.(MasterServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_SubmitJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).SubmitJob(ctx, req.(*JobSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_GetJobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).GetJobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_GetJobStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).GetJobStatus(ctx, req.(*JobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_SubmitPipeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PipelineSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).SubmitPipeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_SubmitPipeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).SubmitPipeline(ctx, req.(*PipelineSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_GetPipelineStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PipelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).GetPipelineStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_GetPipelineStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).GetPipelineStatus(ctx, req.(*PipelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_CancelPipeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PipelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).CancelPipeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_CancelPipeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).CancelPipeline(ctx, req.(*PipelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Master_RestartPipeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PipelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterServer).RestartPipeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Master_RestartPipeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterServer).RestartPipeline(ctx, req.(*PipelineRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -146,12 +456,48 @@ var Master_ServiceDesc = grpc.ServiceDesc{
 	HandlerType: (*MasterServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "AssignMapTask",
-			Handler:    _Master_AssignMapTask_Handler,
+			MethodName: "RegisterWorker",
+			Handler:    _Master_RegisterWorker_Handler,
+		},
+		{
+			MethodName: "RequestTask",
+			Handler:    _Master_RequestTask_Handler,
+		},
+		{
+			MethodName: "ReportTaskDone",
+			Handler:    _Master_ReportTaskDone_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _Master_Heartbeat_Handler,
+		},
+		{
+			MethodName: "SubmitJob",
+			Handler:    _Master_SubmitJob_Handler,
+		},
+		{
+			MethodName: "GetJobStatus",
+			Handler:    _Master_GetJobStatus_Handler,
 		},
 		{
-			MethodName: "AssignReduceTask",
-			Handler:    _Master_AssignReduceTask_Handler,
+			MethodName: "CancelJob",
+			Handler:    _Master_CancelJob_Handler,
+		},
+		{
+			MethodName: "SubmitPipeline",
+			Handler:    _Master_SubmitPipeline_Handler,
+		},
+		{
+			MethodName: "GetPipelineStatus",
+			Handler:    _Master_GetPipelineStatus_Handler,
+		},
+		{
+			MethodName: "CancelPipeline",
+			Handler:    _Master_CancelPipeline_Handler,
+		},
+		{
+			MethodName: "RestartPipeline",
+			Handler:    _Master_RestartPipeline_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -159,8 +505,9 @@ var Master_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	Worker_Map_FullMethodName    = "/protofiles.Worker/Map"
-	Worker_Reduce_FullMethodName = "/protofiles.Worker/Reduce"
+	Worker_Map_FullMethodName            = "/protofiles.Worker/Map"
+	Worker_Reduce_FullMethodName         = "/protofiles.Worker/Reduce"
+	Worker_FetchPartition_FullMethodName = "/protofiles.Worker/FetchPartition"
 )
 
 // WorkerClient is the client API for Worker service.
@@ -169,6 +516,9 @@ const (
 type WorkerClient interface {
 	Map(ctx context.Context, in *MapRequest, opts ...grpc.CallOption) (*TaskResponse, error)
 	Reduce(ctx context.Context, in *ReduceRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	// FetchPartition streams one partition of a map attempt's output, which
+	// stays in the intermediate directory of the worker that ran it.
+	FetchPartition(ctx context.Context, in *FetchPartitionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PartitionChunk], error)
 }
 
 type workerClient struct {
@@ -199,12 +549,34 @@ func (c *workerClient) Reduce(ctx context.Context, in *ReduceRequest, opts ...gr
 	return out, nil
 }
 
+func (c *workerClient) FetchPartition(ctx context.Context, in *FetchPartitionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PartitionChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Worker_ServiceDesc.Streams[0], Worker_FetchPartition_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FetchPartitionRequest, PartitionChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Worker_FetchPartitionClient = grpc.ServerStreamingClient[PartitionChunk]
+
 // WorkerServer is the server API for Worker service.
 // All implementations must embed UnimplementedWorkerServer
 // for forward compatibility.
 type WorkerServer interface {
 	Map(context.Context, *MapRequest) (*TaskResponse, error)
 	Reduce(context.Context, *ReduceRequest) (*TaskResponse, error)
+	// FetchPartition streams one partition of a map attempt's output, which
+	// stays in the intermediate directory of the worker that ran it.
+	FetchPartition(*FetchPartitionRequest, grpc.ServerStreamingServer[PartitionChunk]) error
 	mustEmbedUnimplementedWorkerServer()
 }
 
@@ -221,6 +593,9 @@ func (UnimplementedWorkerServer) Map(context.Context, *MapRequest) (*TaskRespons
 func (UnimplementedWorkerServer) Reduce(context.Context, *ReduceRequest) (*TaskResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Reduce not implemented")
 }
+func (UnimplementedWorkerServer) FetchPartition(*FetchPartitionRequest, grpc.ServerStreamingServer[PartitionChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method FetchPartition not implemented")
+}
 func (UnimplementedWorkerServer) mustEmbedUnimplementedWorkerServer() {}
 func (UnimplementedWorkerServer) testEmbeddedByValue()                {}
 
@@ -278,6 +653,17 @@ func _Worker_Reduce_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Worker_FetchPartition_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchPartitionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkerServer).FetchPartition(m, &grpc.GenericServerStream[FetchPartitionRequest, PartitionChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Worker_FetchPartitionServer = grpc.ServerStreamingServer[PartitionChunk]
+
 // Worker_ServiceDesc is the grpc.ServiceDesc for Worker service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -294,6 +680,12 @@ var Worker_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Worker_Reduce_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FetchPartition",
+			Handler:       _Worker_FetchPartition_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "protofiles/mapreduce.proto",
 }
@@ -0,0 +1,56 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CheckPath returns an error unless the file name is inside the directory
+// root, such as the input root of a worker. Names with ".." components are
+// rejected outright, and the symbolic links of both are resolved, so no link
+// leads out of the root either. A name that does not exist yet, such as a
+// directory about to be created, is checked by its longest existing prefix.
+// An empty root allows every file.
+func CheckPath(root, name string) error {
+	if root == "" {
+		return nil
+	}
+	if slices.Contains(strings.Split(filepath.ToSlash(name), "/"), "..") {
+		return fmt.Errorf("%s is outside %s: \"..\" is not allowed", name, root)
+	}
+	resolvedRoot, err := resolve(root)
+	if err != nil {
+		return fmt.Errorf("bad root directory %s: %v", root, err)
+	}
+	resolved, err := resolve(name)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(resolvedRoot, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is outside %s", name, root)
+	}
+	return nil
+}
+
+// resolve makes name absolute and resolves its symbolic links, keeping the
+// components that do not exist as they are.
+func resolve(name string) (string, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if errors.Is(err, fs.ErrNotExist) && filepath.Dir(abs) != abs {
+		parent, err := resolve(filepath.Dir(abs))
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(parent, filepath.Base(abs)), nil
+	}
+	return resolved, err
+}
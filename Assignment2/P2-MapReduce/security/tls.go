@@ -0,0 +1,78 @@
+// Package security sets up mutual TLS between the master, workers and mrctl,
+// and confines the input files a job may read to an input root.
+package security
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TokenEnv is the environment variable the worker registration token is read
+// from when no -token flag is given. Workers started by the master or the
+// client inherit it.
+const TokenEnv = "MR_WORKER_TOKEN"
+
+// Credentials returns transport credentials for mutual TLS: connections
+// present the certificate in certFile and only accept peers whose
+// certificate is signed by the CA in caFile, both as clients and as servers.
+// If all three files are empty, connections are not encrypted.
+func Credentials(caFile, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	if caFile == "" || certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("mutual TLS needs a CA certificate, a certificate and its key")
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no CA certificate in %s", caFile)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %v", err)
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// Flags are the command-line flags naming the files of Credentials.
+type Flags struct {
+	CA, Cert, Key *string
+}
+
+// AddFlags registers -tls-ca, -tls-cert and -tls-key.
+func AddFlags() Flags {
+	return Flags{
+		CA:   flag.String("tls-ca", "", "CA certificate (PEM) that peers' certificates must be signed by; enables mutual TLS"),
+		Cert: flag.String("tls-cert", "", "Certificate (PEM) presented to peers, signed by the CA"),
+		Key:  flag.String("tls-key", "", "Private key (PEM) of the certificate"),
+	}
+}
+
+// Credentials loads the files named by the flags.
+func (f Flags) Credentials() (credentials.TransportCredentials, error) {
+	return Credentials(*f.CA, *f.Cert, *f.Key)
+}
+
+// Args returns the flags as set, to pass on to worker processes.
+func (f Flags) Args() []string {
+	if *f.CA == "" && *f.Cert == "" && *f.Key == "" {
+		return nil
+	}
+	return []string{"-tls-ca", *f.CA, "-tls-cert", *f.Cert, "-tls-key", *f.Key}
+}
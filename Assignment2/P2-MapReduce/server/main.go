@@ -1,156 +1,104 @@
 package main
 
 import (
-	"bufio"
 	"context"
+	"flag"
 	"fmt"
 	"net"
 	"os"
-  "time"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/example/apps"
 	pb "github.com/example/protofiles"
+	"github.com/example/security"
+	"github.com/example/worker"
 	"google.golang.org/grpc"
 )
 
-type Worker struct {
-	pb.UnimplementedWorkerServer
-  grpcServer *grpc.Server
-}
+func main() {
+	port := flag.String("port", "0", "Port for the Worker service (0 picks a free port)")
+	masterAddr := flag.String("master", "localhost:50051", "Master address")
+	advertise := flag.String("advertise", "", "Host name or IP the master and other workers reach this worker at (default: this machine's host name)")
+	slowdown := flag.Duration("slow", 0, "Extra time to spend on every task, to simulate a straggler")
+	plugins := flag.String("plugin", "", "Comma-separated list of MapReduce app plugins (.so) to load")
+	dir := flag.String("dir", "", "Local directory for this worker's intermediate files (default: the current directory)")
+	mapSlots := flag.Int("map-slots", worker.DefaultMapSlots, "Map tasks to run at once")
+	reduceSlots := flag.Int("reduce-slots", worker.DefaultReduceSlots, "Reduce tasks to run at once")
+	taskMemory := flag.Int64("task-memory", worker.DefaultTaskMemory, "Memory budget of a map or reduce task in bytes; map output beyond half of it is spilled to disk, and larger reduce partitions are merged on disk first")
+	localData := flag.String("local-data", "", "Comma-separated input files or directories on this worker's local disk, to get the map tasks reading them")
+	inputRoot := flag.String("input-root", ".", "Directory map tasks' input files must be in (\"\" to allow any file)")
+	token := flag.String("token", os.Getenv(security.TokenEnv), "Registration token the master requires (default $"+security.TokenEnv+")")
+	tlsFlags := security.AddFlags()
+	flag.Parse()
+	if flag.NArg() > 0 {
+		*port = flag.Arg(0)
+	}
 
-func (w *Worker) Map(ctx context.Context, req *pb.MapRequest) (*pb.TaskResponse, error) {
-	file, err := os.Open(req.Filename)
+	names, err := apps.LoadPlugins(*plugins)
 	if err != nil {
-		return &pb.TaskResponse{Success: false, Message: "Failed to open file"}, err
+		fmt.Println(err)
+		os.Exit(1)
 	}
-	defer file.Close()
-
-	for i := 0; i < int(req.NumReducers); i++ {
-		f, _ := os.Create(fmt.Sprintf("intermediate/mr-%d-%d.txt", req.MapTaskId, i))
-		defer f.Close()
+	for _, name := range names {
+		fmt.Printf("Loaded app %s\n", name)
 	}
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		words := strings.Fields(scanner.Text())
-		for _, word := range words {
-			bucket := hash(word) % int(req.NumReducers)
-			f, _ := os.OpenFile(fmt.Sprintf("intermediate/mr-%d-%d.txt", req.MapTaskId, bucket), os.O_APPEND|os.O_WRONLY, 0644)
-			if req.Mode == "inverted_index" {
-				fmt.Fprintf(f, "%s %s\n", word, filepath.Base(req.Filename))
-			} else if req.Mode == "word_count" {
-				fmt.Fprintf(f, "%s 1\n", word)
-			}
-			f.Close()
-		}
+	creds, err := tlsFlags.Credentials()
+	if err != nil {
+		fmt.Printf("Failed to set up TLS: %v\n", err)
+		os.Exit(1)
 	}
 
-  // w.grpcServer.GracefulStop()
-	return &pb.TaskResponse{Success: true, Message: "Map task completed"}, nil
-}
-
-func (w *Worker) Reduce(ctx context.Context, req *pb.ReduceRequest) (*pb.TaskResponse, error) {
-	if req.Mode == "inverted_index" {
-		return w.reduceInvertedIndex(req)
-	} else if req.Mode == "word_count" {
-		return w.reduceWordCount(req)
+	os.MkdirAll(filepath.Join(*dir, "intermediate"), os.ModePerm)
+	os.MkdirAll("output", os.ModePerm)
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", *port))
+	if err != nil {
+		fmt.Printf("Failed to listen on port %s: %v\n", *port, err)
+		os.Exit(1)
 	}
-  // w.grpcServer.GracefulStop()
-	return &pb.TaskResponse{Success: false, Message: "Invalid mode"}, nil
-}
-
-func (w *Worker) reduceInvertedIndex(req *pb.ReduceRequest) (*pb.TaskResponse, error) {
-	invertedIndex := make(map[string]map[string]bool)
-
-	for mapTaskId := 0; ; mapTaskId++ {
-    fmt.Printf("Iterating %d",mapTaskId)
-		filename := fmt.Sprintf("intermediate/mr-%d-%d.txt", mapTaskId, req.ReduceTaskId)
-		if _, err := os.Stat(filename); os.IsNotExist(err) {
-			break
-		}
-
-		file, _ := os.Open(filename)
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			var word, filename string
-			fmt.Sscanf(scanner.Text(), "%s %s", &word, &filename)
-			if invertedIndex[word] == nil {
-				invertedIndex[word] = make(map[string]bool)
-			}
-			invertedIndex[word][filename] = true
+	// Reducers on other machines fetch map output from this address.
+	host := *advertise
+	if host == "" {
+		if host, err = os.Hostname(); err != nil {
+			host = "localhost"
 		}
-		file.Close()
 	}
-
-	outputFile, _ := os.Create(fmt.Sprintf("output/out-%d.txt", req.ReduceTaskId))
-	defer outputFile.Close()
-
-	for word, files := range invertedIndex {
-		fileList := []string{}
-		for file := range files {
-			fileList = append(fileList, file)
+	address := net.JoinHostPort(host, strconv.Itoa(lis.Addr().(*net.TCPAddr).Port))
+
+	w := worker.New(address, *masterAddr)
+	w.Slowdown = *slowdown
+	w.Dir = *dir
+	w.MapSlots = *mapSlots
+	w.ReduceSlots = *reduceSlots
+	w.TaskMemory = *taskMemory
+	w.Credentials = creds
+	w.Token = *token
+	w.InputRoot = *inputRoot
+	if *localData != "" {
+		for _, p := range strings.Split(*localData, ",") {
+			abs, err := filepath.Abs(p)
+			if err != nil {
+				fmt.Printf("Bad local data path %s: %v\n", p, err)
+				os.Exit(1)
+			}
+			w.LocalData = append(w.LocalData, abs)
 		}
-		fmt.Fprintf(outputFile, "%s %v\n", word, fileList)
 	}
-
-	resp := &pb.TaskResponse{Success: true, Message: "Reduce task completed"}
-  time.Sleep(2 * time.Second) // Give master time to receive response
-  return resp, nil
-}
-
-func (w *Worker) reduceWordCount(req *pb.ReduceRequest) (*pb.TaskResponse, error) {
-	wordCount := make(map[string]int)
-
-	for mapTaskId := 0; ; mapTaskId++ {
-		filename := fmt.Sprintf("intermediate/mr-%d-%d.txt", mapTaskId, req.ReduceTaskId)
-		if _, err := os.Stat(filename); os.IsNotExist(err) {
-			break
-		}
-
-		file, _ := os.Open(filename)
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			var word string
-			var count int
-			fmt.Sscanf(scanner.Text(), "%s %d", &word, &count)
-			wordCount[word] += count
+	grpcServer := grpc.NewServer(grpc.Creds(creds))
+	pb.RegisterWorkerServer(grpcServer, w)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			fmt.Printf("Failed to serve: %v\n", err)
 		}
-		file.Close()
-	}
-
-	outputFile, _ := os.Create(fmt.Sprintf("output/out-%d.txt", req.ReduceTaskId))
-	defer outputFile.Close()
-
-	for word, count := range wordCount {
-		fmt.Fprintf(outputFile, "%s %d\n", word, count)
-	}
-	return &pb.TaskResponse{Success: true, Message: "Reduce task completed"}, nil
-}
-
-func hash(s string) int {
-	h := 0
-	for _, c := range s {
-		h = 31*h + int(c)
-	}
-	return h
-}
-
-func main() {
-  os.MkdirAll("intermediate", os.ModePerm)
-  os.MkdirAll("output", os.ModePerm)
-	port := os.Args[1]
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
-	if err != nil {
-		fmt.Printf("Failed to listen on port %s: %v\n", port, err)
-		return
-	}
-
-	grpcServer := grpc.NewServer()
-	pb.RegisterWorkerServer(grpcServer, &Worker{})
-
-	fmt.Printf("Worker server listening on :%s\n", port)
-	if err := grpcServer.Serve(lis); err != nil {
-		fmt.Printf("Failed to serve: %v\n", err)
+	}()
+	fmt.Printf("Worker server listening on %s\n", address)
+
+	// Pull tasks from the master until the job is over.
+	if err := w.Run(context.Background()); err != nil {
+		fmt.Printf("Worker stopped: %v\n", err)
+		grpcServer.Stop()
+		os.Exit(1)
 	}
+	grpcServer.GracefulStop()
 }
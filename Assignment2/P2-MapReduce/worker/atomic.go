@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// atomicFile is written under a temporary name in the destination directory
+// and only appears under its final name once committed, so readers never see
+// a partially written file and a failed attempt leaves nothing behind.
+type atomicFile struct {
+	*os.File
+	final     string
+	committed bool
+}
+
+func createAtomic(final string) (*atomicFile, error) {
+	f, err := os.CreateTemp(filepath.Dir(final), "."+filepath.Base(final)+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	// CreateTemp uses 0600; committed files get the usual permissions.
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &atomicFile{File: f, final: final}, nil
+}
+
+// Commit flushes the file to disk and renames it into place, replacing any
+// file a previous attempt committed.
+func (f *atomicFile) Commit() error {
+	if err := f.Sync(); err != nil {
+		f.Abort()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), f.final); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	f.committed = true
+	return nil
+}
+
+// Abort discards the temporary file. It does nothing after a successful Commit.
+func (f *atomicFile) Abort() {
+	if f.committed {
+		return
+	}
+	f.Close()
+	os.Remove(f.Name())
+}
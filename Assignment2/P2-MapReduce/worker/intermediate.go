@@ -0,0 +1,297 @@
+package worker
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+)
+
+// Intermediate files hold one JSON-encoded apps.KeyValue per line, sorted by
+// key, compressed with the job's intermediate codec. Values of equal keys keep
+// the order in which the map emitted them.
+
+// intermediateName is the file an attempt of a map task writes in dir for one
+// reduce partition.
+func intermediateName(dir string, mapTaskID, reduceTaskID, attempt int32) string {
+	return filepath.Join(dir, fmt.Sprintf("mr-%d-%d-%d.txt", mapTaskID, reduceTaskID, attempt))
+}
+
+// sortPartition sorts the pairs of one partition by key.
+func sortPartition(kvs []apps.KeyValue) {
+	sort.SliceStable(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+}
+
+// writePartition encodes sorted pairs to w and returns the number of bytes written.
+func writePartition(w io.Writer, kvs []apps.KeyValue) (int64, error) {
+	var n int64
+	for _, kv := range kvs {
+		line, err := json.Marshal(kv)
+		if err != nil {
+			return n, err
+		}
+		line = append(line, '\n')
+		if _, err := w.Write(line); err != nil {
+			return n, err
+		}
+		n += int64(len(line))
+	}
+	return n, nil
+}
+
+// encodedSize is the number of bytes a pair takes in an intermediate file.
+func encodedSize(kv apps.KeyValue) int64 {
+	line, _ := json.Marshal(kv)
+	return int64(len(line) + 1)
+}
+
+// mergeBufferSize is the read buffer of every file being merged, so a merge
+// of n files holds n*mergeBufferSize bytes in buffers.
+const mergeBufferSize = 64 << 10
+
+// mergeSource is the next unread pair of one sorted file.
+type mergeSource struct {
+	name  string
+	in    *countingReader // the file, counting the bytes read from disk
+	zr    io.ReadCloser
+	dec   *json.Decoder
+	kv    apps.KeyValue
+	index int // position of the file, used to break ties between equal keys
+}
+
+type sourceHeap []*mergeSource
+
+func (h sourceHeap) Len() int { return len(h) }
+func (h sourceHeap) Less(i, j int) bool {
+	if h[i].kv.Key != h[j].kv.Key {
+		return h[i].kv.Key < h[j].kv.Key
+	}
+	return h[i].index < h[j].index
+}
+func (h sourceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *sourceHeap) Push(x any)   { *h = append(*h, x.(*mergeSource)) }
+func (h *sourceHeap) Pop() any {
+	old := *h
+	s := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return s
+}
+
+// mergeReader streams the pairs of several sorted files in key order,
+// holding only one pair per file in memory. Pairs with equal keys come in
+// the order of the files, so values keep the order the map tasks emitted
+// them in.
+type mergeReader struct {
+	files   []*os.File
+	sources []*mergeSource
+	size    int64 // total size of the files
+	h       sourceHeap
+	peak    int64 // most bytes held in read buffers and the values of one key
+	records int64 // pairs read so far
+	err     error // first error of Values
+}
+
+// partitionFiles returns the names of the intermediate files in dir of
+// reduce partition R written by the committed attempt A of every map task M,
+// mr-M-R-A.txt, in map task order.
+func partitionFiles(dir string, reduceTaskID int32, mapAttempts []int32) []string {
+	names := make([]string, len(mapAttempts))
+	for mapTaskID, attempt := range mapAttempts {
+		names[mapTaskID] = intermediateName(dir, int32(mapTaskID), reduceTaskID, attempt)
+	}
+	return names
+}
+
+// openRuns opens sorted files of intermediate pairs, compressed with codec,
+// for merging.
+func openRuns(names []string, codec formats.Codec) (*mergeReader, error) {
+	r := &mergeReader{}
+	for i, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		r.files = append(r.files, f)
+		if info, err := f.Stat(); err == nil {
+			r.size += info.Size()
+		}
+		src := &mergeSource{name: name, in: &countingReader{r: f}, index: i}
+		if src.zr, err = codec.NewReader(src.in); err != nil {
+			r.Close()
+			return nil, fmt.Errorf("corrupt intermediate file %s: %v", filepath.Base(name), err)
+		}
+		src.dec = json.NewDecoder(bufio.NewReaderSize(src.zr, mergeBufferSize))
+		r.sources = append(r.sources, src)
+		if err := r.advance(src); err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+	r.peak = r.buffers()
+	return r, nil
+}
+
+// buffers returns the bytes held in read buffers.
+func (r *mergeReader) buffers() int64 {
+	return int64(len(r.files)) * mergeBufferSize
+}
+
+// advance reads the next pair of src and puts it back on the heap, unless the
+// file is exhausted.
+func (r *mergeReader) advance(src *mergeSource) error {
+	src.kv = apps.KeyValue{}
+	if err := src.dec.Decode(&src.kv); err == io.EOF {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("corrupt intermediate file %s: %v", filepath.Base(src.name), err)
+	}
+	heap.Push(&r.h, src)
+	return nil
+}
+
+// Next returns the next pair. ok is false once every file is exhausted.
+func (r *mergeReader) Next() (kv apps.KeyValue, ok bool, err error) {
+	if r.h.Len() == 0 {
+		return apps.KeyValue{}, false, nil
+	}
+	src := heap.Pop(&r.h).(*mergeSource)
+	kv = src.kv
+	if err := r.advance(src); err != nil {
+		return apps.KeyValue{}, false, err
+	}
+	r.records++
+	return kv, true, nil
+}
+
+// NextKey returns the key of the next pair without reading it. ok is false
+// once every file is exhausted.
+func (r *mergeReader) NextKey() (key string, ok bool) {
+	if r.h.Len() == 0 {
+		return "", false
+	}
+	return r.h[0].kv.Key, true
+}
+
+// Values returns the values of key, the next key, read as they are ranged
+// over, so only one of them is held at a time. Ranging over them again
+// continues after the last value read. Reading stops at the first error,
+// which Err returns.
+func (r *mergeReader) Values(key string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for r.err == nil && r.h.Len() > 0 && r.h[0].kv.Key == key {
+			kv, _, err := r.Next()
+			if err != nil {
+				r.err = err
+				return
+			}
+			r.peak = max(r.peak, r.buffers()+int64(len(key)+len(kv.Value)))
+			if !yield(kv.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the error that stopped Values, if any.
+func (r *mergeReader) Err() error {
+	return r.err
+}
+
+// NextGroup returns the next key and all of its values. ok is false once
+// every file is exhausted. Whenever the values held grow past limit bytes
+// and combine is not nil, the values so far are replaced by their combined
+// values, so a key with many values of a combiner app takes bounded memory.
+// Apps without a combiner are bounded only if they take their values from
+// Values instead.
+func (r *mergeReader) NextGroup(limit int64, combine func(key string, values []string) []string) (key string, values []string, ok bool, err error) {
+	key, ok = r.NextKey()
+	if !ok {
+		return "", nil, false, nil
+	}
+	var held int64
+	for v := range r.Values(key) {
+		values = append(values, v)
+		held += int64(len(v))
+		r.peak = max(r.peak, r.buffers()+int64(len(key))+held)
+		if held > limit && combine != nil {
+			values = combine(key, values)
+			held = 0
+			for _, v := range values {
+				held += int64(len(v))
+			}
+			// Values that do not combine any further are held anyway, but
+			// not combined again until they doubled.
+			limit = max(limit, 2*held)
+		}
+	}
+	if r.err != nil {
+		return "", nil, false, r.err
+	}
+	return key, values, true, nil
+}
+
+// Records returns the number of pairs read so far, including values that
+// were combined.
+func (r *mergeReader) Records() int64 {
+	return r.records
+}
+
+// Peak returns the most bytes held at once in read buffers and the values
+// of one key.
+func (r *mergeReader) Peak() int64 {
+	return r.peak
+}
+
+// Progress returns the fraction of the files' bytes consumed so far.
+func (r *mergeReader) Progress() float64 {
+	if r.size == 0 {
+		return 1
+	}
+	var read int64
+	for _, src := range r.sources {
+		read += src.in.n
+	}
+	return min(float64(read)/float64(r.size), 1)
+}
+
+func (r *mergeReader) Close() {
+	for _, src := range r.sources {
+		src.zr.Close()
+	}
+	for _, f := range r.files {
+		f.Close()
+	}
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+
+	pb "github.com/example/protofiles"
+)
+
+// taskKey identifies one attempt of a task.
+type taskKey struct {
+	job     string
+	kind    pb.TaskType
+	id      int32
+	attempt int32
+}
+
+// runningTask is an attempt executing on this worker.
+type runningTask struct {
+	progress float64
+	cancel   context.CancelFunc
+}
+
+// startTask tracks an attempt so its progress is sent with heartbeats and the
+// master can abort it.
+func (w *Worker) startTask(key taskKey, cancel context.CancelFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.running[key] = &runningTask{cancel: cancel}
+}
+
+func (w *Worker) finishTask(key taskKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.running, key)
+}
+
+// setProgress records the fraction of an attempt that is done. It does nothing
+// for tasks that were not started through the master, e.g. direct Map calls.
+func (w *Worker) setProgress(key taskKey, progress float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t, ok := w.running[key]; ok {
+		t.progress = progress
+	}
+}
+
+// progressReport lists the running attempts for a heartbeat.
+func (w *Worker) progressReport() []*pb.TaskProgress {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var report []*pb.TaskProgress
+	for key, t := range w.running {
+		report = append(report, &pb.TaskProgress{JobId: key.job, Type: key.kind, TaskId: key.id, Attempt: key.attempt, Progress: t.progress})
+	}
+	return report
+}
+
+// abort cancels attempts the master no longer wants.
+func (w *Worker) abort(attempts []*pb.TaskProgress) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, a := range attempts {
+		if t, ok := w.running[taskKey{a.JobId, a.Type, a.TaskId, a.Attempt}]; ok {
+			t.cancel()
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	pb "github.com/example/protofiles"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pollInterval      = 500 * time.Millisecond // wait before asking again when there is no task
+	heartbeatInterval = time.Second
+	registerTimeout   = 30 * time.Second
+	masterTimeout     = 30 * time.Second // how long the master may be unreachable, e.g. while it restarts
+)
+
+// Run registers with the master and executes the tasks it hands out until the
+// master tells it to exit or stays unreachable for longer than masterTimeout.
+// Up to MapSlots map and ReduceSlots reduce attempts run at once; the worker
+// only asks for tasks of a kind it has a free slot for.
+func (w *Worker) Run(ctx context.Context) error {
+	conn, err := w.dial(w.masterAddr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to master: %v", err)
+	}
+	defer conn.Close()
+	w.master = pb.NewMasterClient(conn)
+
+	if err := w.register(ctx); err != nil {
+		return err
+	}
+	// Running attempts are cancelled and waited for when Run returns.
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go w.sendHeartbeats(ctx)
+
+	free := map[pb.TaskType]int{pb.TaskType_MAP_TASK: max(w.MapSlots, 1), pb.TaskType_REDUCE_TASK: max(w.ReduceSlots, 1)}
+	done := make(chan pb.TaskType, free[pb.TaskType_MAP_TASK]+free[pb.TaskType_REDUCE_TASK])
+	// wait blocks until an attempt finishes and frees its slot, or until d
+	// passed if d > 0.
+	wait := func(d time.Duration) {
+		var timeout <-chan time.Time
+		if d > 0 {
+			timeout = time.After(d)
+		}
+		select {
+		case kind := <-done:
+			free[kind]++
+		case <-timeout:
+		case <-ctx.Done():
+		}
+	}
+
+	var unreachableSince time.Time
+	for ctx.Err() == nil {
+		for len(done) > 0 {
+			free[<-done]++
+		}
+		if free[pb.TaskType_MAP_TASK] == 0 && free[pb.TaskType_REDUCE_TASK] == 0 {
+			wait(0)
+			continue
+		}
+		assignment, err := w.master.RequestTask(ctx, &pb.TaskRequest{
+			WorkerId:        w.workerID(),
+			FreeMapSlots:    int32(free[pb.TaskType_MAP_TASK]),
+			FreeReduceSlots: int32(free[pb.TaskType_REDUCE_TASK]),
+		})
+		if status.Code(err) == codes.NotFound {
+			// The master declared this worker dead; join again under a new ID.
+			if err := w.register(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			if unreachableSince.IsZero() {
+				unreachableSince = time.Now()
+			} else if time.Since(unreachableSince) > masterTimeout {
+				return fmt.Errorf("master unreachable: %v", err)
+			}
+			wait(pollInterval)
+			continue
+		}
+		unreachableSince = time.Time{}
+
+		switch assignment.Type {
+		case pb.TaskType_EXIT_TASK:
+			fmt.Println("Master is shutting down, worker exiting")
+			return nil
+		case pb.TaskType_MAP_TASK, pb.TaskType_REDUCE_TASK:
+			free[assignment.Type]--
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.execute(ctx, assignment)
+				done <- assignment.Type
+			}()
+		default:
+			wait(pollInterval)
+		}
+	}
+	return nil
+}
+
+// dial connects to the master or to another worker, with w.Credentials.
+func (w *Worker) dial(addr string) (*grpc.ClientConn, error) {
+	creds := w.Credentials
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	return grpc.Dial(addr, append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, w.DialOptions...)...)
+}
+
+// register joins the master's worker pool, waiting for the master to come up.
+func (w *Worker) register(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, registerTimeout)
+	defer cancel()
+	res, err := w.master.RegisterWorker(ctx, &pb.RegisterWorkerRequest{
+		Address:     w.Address,
+		MapSlots:    int32(w.MapSlots),
+		ReduceSlots: int32(w.ReduceSlots),
+		LocalData:   w.LocalData,
+		Token:       w.Token,
+	}, grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Errorf("failed to register with master: %v", err)
+	}
+	w.mu.Lock()
+	w.id = res.WorkerId
+	w.mu.Unlock()
+	fmt.Printf("Registered with master as %s\n", res.WorkerId)
+	return nil
+}
+
+func (w *Worker) workerID() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.id
+}
+
+// execute runs one task attempt and reports its outcome to the master. The
+// attempt is cancelled if the master reports, through a heartbeat, that
+// another attempt of the task already won. Output of an attempt the master
+// does not accept is removed.
+func (w *Worker) execute(ctx context.Context, assignment *pb.TaskAssignment) {
+	var taskID int32
+	if assignment.Type == pb.TaskType_MAP_TASK {
+		taskID = assignment.Map.MapTaskId
+	} else {
+		taskID = assignment.Reduce.ReduceTaskId
+	}
+	key := taskKey{assignment.JobId, assignment.Type, taskID, assignment.Attempt}
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w.startTask(key, cancel)
+	defer w.finishTask(key)
+
+	var res *pb.TaskResponse
+	var err error
+	if err = w.slowDown(taskCtx); err == nil {
+		if assignment.Type == pb.TaskType_MAP_TASK {
+			res, err = w.Map(taskCtx, assignment.Map)
+		} else {
+			res, err = w.Reduce(taskCtx, assignment.Reduce)
+		}
+	}
+	if res == nil {
+		res = &pb.TaskResponse{}
+	}
+	if err != nil {
+		res.Success = false
+		res.Message = fmt.Sprintf("%s: %v", res.Message, err)
+	}
+	if taskCtx.Err() != nil && ctx.Err() == nil {
+		fmt.Printf("Aborted %s %s %d attempt %d at the master's request\n", assignment.JobId, assignment.Type, taskID, assignment.Attempt)
+	}
+
+	ack, err := w.master.ReportTaskDone(ctx, &pb.TaskReport{
+		WorkerId: w.workerID(),
+		JobId:    assignment.JobId,
+		Type:     assignment.Type,
+		TaskId:   taskID,
+		Attempt:  assignment.Attempt,
+		Result:   res,
+	})
+	if err != nil {
+		fmt.Printf("Failed to report %s %d: %v\n", assignment.Type, taskID, err)
+		// An accepted reduce output was already renamed by the master, so the
+		// attempt's file is only left over if the report never arrived, e.g.
+		// because the master restarted. Map output is kept: its name is unique
+		// to the attempt and a restarted master may still recover it.
+		if assignment.Type == pb.TaskType_REDUCE_TASK && res.Success {
+			w.removeOutput(assignment, res)
+		}
+		return
+	}
+	if !ack.Accepted && res.Success {
+		w.removeOutput(assignment, res)
+	}
+}
+
+// slowDown waits for the configured Slowdown, or until ctx is cancelled.
+func (w *Worker) slowDown(ctx context.Context) error {
+	if w.Slowdown <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(w.Slowdown):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// removeOutput deletes the files of a successful attempt that the master
+// discarded.
+func (w *Worker) removeOutput(assignment *pb.TaskAssignment, res *pb.TaskResponse) {
+	if assignment.Type == pb.TaskType_MAP_TASK {
+		req := assignment.Map
+		dir, err := w.intermediateDir(req.IntermediateDir)
+		if err != nil {
+			return
+		}
+		for r := int32(0); r < req.NumReducers; r++ {
+			os.Remove(intermediateName(dir, req.MapTaskId, r, req.Attempt))
+		}
+	} else if res.OutputFile != "" {
+		os.Remove(res.OutputFile)
+	}
+}
+
+// sendHeartbeats tells the master this worker is alive and how far its
+// running attempts are.
+func (w *Worker) sendHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		hbCtx, cancel := context.WithTimeout(ctx, heartbeatInterval)
+		res, err := w.master.Heartbeat(hbCtx, &pb.HeartbeatRequest{WorkerId: w.workerID(), Tasks: w.progressReport()})
+		cancel()
+		if err == nil {
+			w.abort(res.Abort)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	pb "github.com/example/protofiles"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chunkSize is the size of the chunks a partition is streamed in.
+const chunkSize = 64 << 10
+
+// FetchPartition streams one partition of a map attempt's output from this
+// worker's intermediate directory to a reducer.
+func (w *Worker) FetchPartition(req *pb.FetchPartitionRequest, stream pb.Worker_FetchPartitionServer) error {
+	dir, err := w.intermediateDir(req.IntermediateDir)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+	name := intermediateName(dir, req.MapTaskId, req.ReduceTaskId, req.Attempt)
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return status.Errorf(codes.NotFound, "no output of %s map task %d attempt %d for reduce task %d",
+			req.JobId, req.MapTaskId, req.Attempt, req.ReduceTaskId)
+	} else if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.PartitionChunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return status.Errorf(codes.Internal, "failed to read %s: %v", name, err)
+		}
+	}
+}
+
+// fetchPartitions copies this reducer's partition of every map task's output
+// into dir, under the names this job's map tasks would give them, from the
+// workers listed in req.MapAddresses. Outputs reused from an earlier job are
+// fetched from where req.MapDirs says. It goes through all map tasks even if
+// some fail, and returns the attempts whose output could not be fetched by
+// map task ID, so the master can run them again.
+func (w *Worker) fetchPartitions(ctx context.Context, req *pb.ReduceRequest, dir string, progress func(float64)) (map[int32]int32, error) {
+	if len(req.MapAddresses) != len(req.MapAttempts) {
+		return nil, fmt.Errorf("got %d map addresses for %d map tasks", len(req.MapAddresses), len(req.MapAttempts))
+	}
+	if len(req.MapDirs) > 0 && (len(req.MapDirs) != len(req.MapAttempts) || len(req.MapOutputIds) != len(req.MapAttempts)) {
+		return nil, fmt.Errorf("got %d map directories for %d map tasks", len(req.MapDirs), len(req.MapAttempts))
+	}
+	conns := make(map[string]*grpc.ClientConn)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	lost := make(map[int32]int32)
+	var lastErr error
+	for m, attempt := range req.MapAttempts {
+		mapTaskID := int32(m)
+		addr := req.MapAddresses[m]
+		conn, ok := conns[addr]
+		if !ok {
+			var err error
+			if conn, err = w.dial(addr); err != nil {
+				return nil, fmt.Errorf("failed to connect to worker %s: %v", addr, err)
+			}
+			conns[addr] = conn
+		}
+		fetch := &pb.FetchPartitionRequest{
+			JobId:           req.JobId,
+			IntermediateDir: req.IntermediateDir,
+			MapTaskId:       mapTaskID,
+			ReduceTaskId:    req.ReduceTaskId,
+			Attempt:         attempt,
+		}
+		if len(req.MapDirs) > 0 {
+			fetch.IntermediateDir, fetch.MapTaskId = req.MapDirs[m], req.MapOutputIds[m]
+		}
+		err := fetchPartition(ctx, pb.NewWorkerClient(conn), fetch, intermediateName(dir, mapTaskID, req.ReduceTaskId, attempt))
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			fmt.Printf("Failed to fetch map task %d attempt %d from %s: %v\n", mapTaskID, attempt, addr, err)
+			lost[mapTaskID] = attempt
+			lastErr = err
+		}
+		progress(float64(m+1) / float64(len(req.MapAttempts)))
+	}
+	if len(lost) > 0 {
+		ids := make([]int, 0, len(lost))
+		for id := range lost {
+			ids = append(ids, int(id))
+		}
+		sort.Ints(ids)
+		return lost, fmt.Errorf("could not fetch the output of map tasks %v: %v", ids, lastErr)
+	}
+	return nil, nil
+}
+
+// fetchPartition streams one partition from a worker into the file name.
+func fetchPartition(ctx context.Context, client pb.WorkerClient, req *pb.FetchPartitionRequest, name string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.FetchPartition(ctx, req)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return f.Close()
+		} else if err != nil {
+			return err
+		}
+		if _, err := f.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
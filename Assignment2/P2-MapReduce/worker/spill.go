@@ -0,0 +1,292 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+)
+
+// Map and reduce attempts keep their memory within the worker's TaskMemory
+// budget. A map attempt holds its output in memory until it takes up half of
+// the budget, and then spills it to disk as one sorted run per partition; at
+// the end, the runs of each partition are merged into its intermediate file.
+// In a reduce attempt, half of the budget goes to the read buffers of the
+// files merged at once, and half to the values of the key being reduced. A
+// reducer with more map outputs than its buffers allow first merges them in
+// groups into longer sorted runs on disk, in as many passes as it takes, and
+// only merges the last runs into the reduce function. A map attempt merges
+// its runs the same way.
+//
+// Memory is estimated from the bytes of the keys and values held and the
+// read buffers, not measured, so the process uses somewhat more.
+
+// DefaultTaskMemory is the memory budget of a map or reduce attempt.
+const DefaultTaskMemory = 64 << 20
+
+// mergeFanIn returns how many files a reduce attempt with the given budget
+// merges at once.
+func mergeFanIn(budget int64) int {
+	return max(2, int(budget/2/mergeBufferSize))
+}
+
+// valueLimit returns how many bytes of values of one key a reduce attempt
+// with the given budget holds before it combines them.
+func valueLimit(budget int64) int64 {
+	return max(budget/2, 1)
+}
+
+// mergeRuns merges the sorted files runs, fanIn at a time, into sorted runs
+// in dir until at most fanIn are left, and returns their names and the most
+// memory a merge held in buffers. Runs are merged in order, so the values of
+// equal keys keep their order. Merged files are removed. Runs are read and
+// written with codec. The pairs written are counted under spilled.
+func mergeRuns(ctx context.Context, dir string, runs []string, fanIn int, codec formats.Codec, counters apps.Counters, spilled string) ([]string, int64, error) {
+	var peak int64
+	for pass := 1; len(runs) > fanIn; pass++ {
+		var merged []string
+		for i := 0; i < len(runs); i += fanIn {
+			group := runs[i:min(i+fanIn, len(runs))]
+			if len(group) == 1 {
+				merged = append(merged, group[0])
+				continue
+			}
+			name := filepath.Join(dir, fmt.Sprintf("run-%d-%d.txt", pass, len(merged)))
+			n, err := mergeInto(name, group, codec)
+			if err != nil {
+				return nil, peak, err
+			}
+			counters.Add(spilled, n)
+			// One read buffer per run and one write buffer.
+			peak = max(peak, int64(len(group)+1)*mergeBufferSize)
+			for _, run := range group {
+				os.Remove(run)
+			}
+			merged = append(merged, name)
+			if ctx.Err() != nil {
+				return nil, peak, ctx.Err()
+			}
+		}
+		runs = merged
+	}
+	return runs, peak, nil
+}
+
+// mergeInto merges sorted runs into one sorted file and returns the number
+// of pairs written.
+func mergeInto(name string, runs []string, codec formats.Codec) (int64, error) {
+	r, err := openRuns(runs, codec)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	var n int64
+	err = writeRun(name, codec, func(w io.Writer) error {
+		for {
+			kv, ok, err := r.Next()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			if _, err := writePartition(w, []apps.KeyValue{kv}); err != nil {
+				return err
+			}
+			n++
+		}
+	})
+	return n, err
+}
+
+// writeRun creates the file name and writes a run to it with write, through
+// a buffer and codec.
+func writeRun(name string, codec formats.Codec, write func(w io.Writer) error) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := codec.NewWriter(f)
+	w := bufio.NewWriterSize(zw, mergeBufferSize)
+	if err := write(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// mapBuffer holds the output of a map attempt by partition. Once the pairs
+// held outgrow their limit, Spill writes each partition, sorted, or combined
+// if the attempt has a combiner, to a run of its own in dir/<partition>, and
+// WritePartition merges the runs of a partition into its intermediate file.
+// Without spills, WritePartition writes the pairs held directly.
+type mapBuffer struct {
+	dir      string
+	codec    formats.Codec
+	combiner apps.Combiner // nil if the app has none
+	budget   int64
+	counters apps.Counters
+
+	buckets  [][]apps.KeyValue
+	held     int64      // estimated bytes of the pairs in buckets
+	runs     [][]string // spilled runs of every partition, in order
+	spills   int
+	peak     int64 // most bytes held at once in pairs or merge buffers
+	rawBytes int64 // encoded bytes of the pairs passed to the combiner
+}
+
+func newMapBuffer(dir string, partitions int, codec formats.Codec, combiner apps.Combiner, budget int64, counters apps.Counters) *mapBuffer {
+	return &mapBuffer{
+		dir:      dir,
+		codec:    codec,
+		combiner: combiner,
+		budget:   budget,
+		counters: counters,
+		buckets:  make([][]apps.KeyValue, partitions),
+		runs:     make([][]string, partitions),
+	}
+}
+
+// Add holds a pair of a partition.
+func (b *mapBuffer) Add(partition int, kv apps.KeyValue) {
+	b.buckets[partition] = append(b.buckets[partition], kv)
+	b.held += int64(len(kv.Key) + len(kv.Value))
+	b.peak = max(b.peak, b.held)
+}
+
+// Full reports whether the pairs held take up half of the budget.
+func (b *mapBuffer) Full() bool {
+	return b.held >= max(b.budget/2, 1)
+}
+
+// Spill writes the pairs held to a new run of every partition that has any,
+// and drops them.
+func (b *mapBuffer) Spill() error {
+	for i, kvs := range b.buckets {
+		if len(kvs) == 0 {
+			continue
+		}
+		kvs = b.prepare(kvs)
+		dir := filepath.Join(b.dir, strconv.Itoa(i))
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+		name := filepath.Join(dir, fmt.Sprintf("spill-%d.txt", b.spills))
+		err := writeRun(name, b.codec, func(w io.Writer) error {
+			_, err := writePartition(w, kvs)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		b.counters.Add(apps.MapSpilledRecords, int64(len(kvs)))
+		b.runs[i] = append(b.runs[i], name)
+		b.buckets[i] = nil
+	}
+	b.spills++
+	b.held = 0
+	return nil
+}
+
+// prepare sorts the pairs of a partition, or combines them if there is a
+// combiner.
+func (b *mapBuffer) prepare(kvs []apps.KeyValue) []apps.KeyValue {
+	if b.combiner == nil {
+		sortPartition(kvs)
+		return kvs
+	}
+	for _, kv := range kvs {
+		b.rawBytes += encodedSize(kv)
+	}
+	b.counters.Add(apps.CombineInputRecords, int64(len(kvs)))
+	kvs = combineBucket(b.combiner, kvs)
+	b.counters.Add(apps.CombineOutputRecords, int64(len(kvs)))
+	return kvs
+}
+
+// WritePartition writes the sorted, or combined, pairs of a partition to w
+// and returns the number of bytes written. Once anything was spilled, the
+// pairs held are spilled too and the partition's runs are merged, combining
+// the values of each key again if there is a combiner.
+func (b *mapBuffer) WritePartition(ctx context.Context, i int, w io.Writer) (int64, error) {
+	if b.spills == 0 {
+		kvs := b.prepare(b.buckets[i])
+		b.buckets[i] = nil
+		n, err := writePartition(w, kvs)
+		b.counters.Add(apps.MapSpilledRecords, int64(len(kvs)))
+		return n, err
+	}
+	if b.held > 0 {
+		if err := b.Spill(); err != nil {
+			return 0, err
+		}
+	}
+	runs, mergePeak, err := mergeRuns(ctx, filepath.Join(b.dir, strconv.Itoa(i)), b.runs[i], mergeFanIn(b.budget), b.codec, b.counters, apps.MapSpilledRecords)
+	if err != nil {
+		return 0, err
+	}
+	b.peak = max(b.peak, mergePeak)
+	input, err := openRuns(runs, b.codec)
+	if err != nil {
+		return 0, err
+	}
+	defer input.Close()
+	var n int64
+	for {
+		var kvs []apps.KeyValue
+		if b.combiner != nil {
+			read := input.Records()
+			key, values, ok, err := input.NextGroup(valueLimit(b.budget), b.combiner.Combine)
+			if err != nil {
+				return n, err
+			}
+			if !ok {
+				break
+			}
+			b.counters.Add(apps.CombineInputRecords, input.Records()-read)
+			for _, v := range b.combiner.Combine(key, values) {
+				kvs = append(kvs, apps.KeyValue{Key: key, Value: v})
+			}
+			b.counters.Add(apps.CombineOutputRecords, int64(len(kvs)))
+		} else {
+			kv, ok, err := input.Next()
+			if err != nil {
+				return n, err
+			}
+			if !ok {
+				break
+			}
+			kvs = []apps.KeyValue{kv}
+		}
+		written, err := writePartition(w, kvs)
+		n += written
+		if err != nil {
+			return n, err
+		}
+		b.counters.Add(apps.MapSpilledRecords, int64(len(kvs)))
+	}
+	b.peak = max(b.peak, input.Peak())
+	return n, nil
+}
+
+// Peak returns the most bytes held at once in pairs or merge buffers.
+func (b *mapBuffer) Peak() int64 {
+	return b.peak
+}
+
+// RawBytes returns the encoded bytes of the pairs passed to the combiner.
+func (b *mapBuffer) RawBytes() int64 {
+	return b.rawBytes
+}
@@ -0,0 +1,464 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/example/apps"
+	"github.com/example/formats"
+	pb "github.com/example/protofiles"
+	"github.com/example/security"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// Worker executes map and reduce tasks. Tasks are pulled from the master by
+// Run, and can also be called directly through the Worker gRPC service.
+type Worker struct {
+	pb.UnimplementedWorkerServer
+	Address     string // address of this worker's Worker service
+	masterAddr  string
+	Slowdown    time.Duration     // extra time spent on every task, to simulate a straggler
+	Dir         string            // local directory the intermediate directories are in, "" for the current directory
+	MapSlots    int               // map attempts run at once
+	ReduceSlots int               // reduce attempts run at once
+	LocalData   []string          // absolute input paths on this worker's local disk, for data-local map tasks
+	TaskMemory  int64             // memory budget of a map or reduce attempt in bytes, see mapBuffer and mergeRuns
+	DialOptions []grpc.DialOption // extra options for connections to the master and other workers
+
+	// Credentials secure the connections to the master and other workers, or
+	// none if nil. Token is sent when registering, and InputRoot is the
+	// directory the input files of map tasks must be in, "" for anywhere.
+	Credentials credentials.TransportCredentials
+	Token       string
+	InputRoot   string
+
+	mu      sync.Mutex
+	id      string // ID assigned by the master at registration
+	master  pb.MasterClient
+	running map[taskKey]*runningTask
+}
+
+// Default slot counts of a worker.
+const (
+	DefaultMapSlots    = 2
+	DefaultReduceSlots = 2
+)
+
+// New returns a worker serving on address that pulls tasks from the master at masterAddr.
+func New(address, masterAddr string) *Worker {
+	return &Worker{
+		Address:     address,
+		masterAddr:  masterAddr,
+		MapSlots:    DefaultMapSlots,
+		ReduceSlots: DefaultReduceSlots,
+		TaskMemory:  DefaultTaskMemory,
+		running:     make(map[taskKey]*runningTask),
+	}
+}
+
+// Map runs the app's map function over every record of one input split, as
+// read by the job's input format, and partitions its output into one
+// intermediate file per reducer.
+func (w *Worker) Map(ctx context.Context, req *pb.MapRequest) (*pb.TaskResponse, error) {
+	if req.NumReducers < 1 || req.NumReducers > apps.MaxReducers {
+		return &pb.TaskResponse{Success: false, Message: "Invalid number of reducers"},
+			status.Errorf(codes.InvalidArgument, "need 1 to %d reducers, got %d", apps.MaxReducers, req.NumReducers)
+	}
+	app, err := apps.New(req.Mode, req.Params)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Invalid mode"}, err
+	}
+	partitioner, err := apps.NewPartitioner(req.Partitioner, req.PartitionBoundaries)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Invalid partitioner"}, err
+	}
+	format, err := formats.LookupInput(req.InputFormat)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Invalid input format"}, err
+	}
+	codec, err := formats.LookupCodec(req.IntermediateCodec)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Invalid intermediate codec"}, err
+	}
+	// Anyone who can call Map could otherwise have the worker read any file.
+	if err := security.CheckPath(w.InputRoot, req.Filename); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Input file not allowed"}, err
+	}
+	key := taskKey{req.JobId, pb.TaskType_MAP_TASK, req.MapTaskId, req.Attempt}
+	dir, err := w.intermediateDir(req.IntermediateDir)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Intermediate directory not allowed"}, err
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to create intermediate directory"}, err
+	}
+	input, err := openSplit(req.Filename, req.Offset, req.Length)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to open file"}, err
+	}
+	defer input.Close()
+	w.setProgress(key, 0.1)
+
+	// Partitions are buffered, compressed and written to temporary files that
+	// are only renamed into place once the whole task succeeded.
+	files := make([]*atomicFile, req.NumReducers)
+	compressed := make([]*countingWriter, req.NumReducers)
+	compressors := make([]io.WriteCloser, req.NumReducers)
+	writers := make([]*bufio.Writer, req.NumReducers)
+	for i := range files {
+		f, err := createAtomic(intermediateName(dir, req.MapTaskId, int32(i), req.Attempt))
+		if err != nil {
+			return &pb.TaskResponse{Success: false, Message: "Failed to create intermediate file"}, err
+		}
+		defer f.Abort()
+		files[i] = f
+		compressed[i] = &countingWriter{w: f}
+		compressors[i] = codec.NewWriter(compressed[i])
+		writers[i] = bufio.NewWriter(compressors[i])
+	}
+
+	counters, appCounters := apps.Counters{}, apps.Counters{}
+	mapFunc := app.Map
+	if cm, ok := app.(apps.CountingMapper); ok {
+		mapFunc = func(filename, contents string) []apps.KeyValue {
+			return cm.MapWithCounters(filename, contents, appCounters)
+		}
+	}
+	budget := w.TaskMemory
+	if budget <= 0 {
+		budget = DefaultTaskMemory
+	}
+	spillDir := filepath.Join(dir, fmt.Sprintf("map-%d-attempt-%d", req.MapTaskId, req.Attempt))
+	defer os.RemoveAll(spillDir)
+	combiner, combine := app.(apps.Combiner)
+	buf := newMapBuffer(spillDir, int(req.NumReducers), codec, combiner, budget, counters)
+	var spillErr error
+	err = format.ReadRecords(input, func(record string) error {
+		counters.Add(apps.MapInputRecords, 1)
+		kvs := mapFunc(req.Filename, record)
+		counters.Add(apps.MapOutputRecords, int64(len(kvs)))
+		for _, kv := range kvs {
+			buf.Add(partitioner.Partition(kv.Key, int(req.NumReducers)), kv)
+		}
+		if buf.Full() {
+			if spillErr = buf.Spill(); spillErr != nil {
+				return spillErr
+			}
+		}
+		return ctx.Err()
+	})
+	if spillErr != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to spill map output"}, spillErr
+	}
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to read input"}, err
+	}
+	w.setProgress(key, 0.5)
+	var rawBytes, writtenBytes int64
+	for i := range writers {
+		n, err := buf.WritePartition(ctx, i, writers[i])
+		if err != nil {
+			return &pb.TaskResponse{Success: false, Message: "Failed to write intermediate file"}, err
+		}
+		counters.Add(apps.MapPartitionBytes+strconv.Itoa(i), n)
+		writtenBytes += n
+		w.setProgress(key, 0.5+0.5*float64(i+1)/float64(len(writers)))
+	}
+	rawBytes = writtenBytes
+	if combine {
+		rawBytes = buf.RawBytes()
+	}
+	var compressedBytes int64
+	for i, bw := range writers {
+		if err := bw.Flush(); err != nil {
+			return &pb.TaskResponse{Success: false, Message: "Failed to write intermediate file"}, err
+		}
+		if err := compressors[i].Close(); err != nil {
+			return &pb.TaskResponse{Success: false, Message: "Failed to write intermediate file"}, err
+		}
+		compressedBytes += compressed[i].n
+	}
+	if ctx.Err() != nil {
+		return &pb.TaskResponse{Success: false, Message: "Map task aborted"}, ctx.Err()
+	}
+	for _, f := range files {
+		if err := f.Commit(); err != nil {
+			return &pb.TaskResponse{Success: false, Message: "Failed to commit intermediate file"}, err
+		}
+	}
+
+	message := "Map task completed"
+	if combine && rawBytes > 0 {
+		message = fmt.Sprintf("Map task completed, combiner reduced intermediate data from %d to %d bytes (%.1f%% smaller)",
+			rawBytes, writtenBytes, 100*float64(rawBytes-writtenBytes)/float64(rawBytes))
+	}
+	counters.AddAll(apps.AppCounterPrefix, appCounters)
+	resp := &pb.TaskResponse{
+		Success:              true,
+		Message:              message,
+		RawIntermediateBytes: rawBytes,
+		IntermediateBytes:    writtenBytes,
+		BufferedBytesPeak:    buf.Peak(),
+		Counters:             counters,
+	}
+	if codec.Name() != formats.None {
+		resp.UncompressedBytes, resp.CompressedBytes = writtenBytes, compressedBytes
+	}
+	return resp, nil
+}
+
+// combineBucket runs the app's combiner over the pairs of one partition,
+// grouped by key in sorted key order.
+func combineBucket(c apps.Combiner, kvs []apps.KeyValue) []apps.KeyValue {
+	groups := make(map[string][]string)
+	for _, kv := range kvs {
+		groups[kv.Key] = append(groups[kv.Key], kv.Value)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	combined := make([]apps.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range c.Combine(key, groups[key]) {
+			combined = append(combined, apps.KeyValue{Key: key, Value: value})
+		}
+	}
+	return combined
+}
+
+// Reduce fetches this reducer's partition of every map task's output from the
+// workers holding them, then streams a k-way merge of the sorted partitions
+// and writes one pair per key, in key order, with the app's reduce result in
+// the job's output format, or the pairs of a RecordReducer or StreamReducer.
+// Memory stays within w.TaskMemory: partitions beyond the merge fan-in are
+// first merged into runs on disk, and the values of a key that outgrow their
+// share are combined if the app is a Combiner, or never held at all if it is
+// a StreamReducer. The output is written to an attempt-specific file that
+// the master renames to out-<reduce><ext> if this attempt is the one it
+// keeps.
+func (w *Worker) Reduce(ctx context.Context, req *pb.ReduceRequest) (*pb.TaskResponse, error) {
+	if req.ReduceTaskId < 0 || req.ReduceTaskId >= apps.MaxReducers {
+		return &pb.TaskResponse{Success: false, Message: "Invalid reduce task"},
+			status.Errorf(codes.InvalidArgument, "reduce task %d is not between 0 and %d", req.ReduceTaskId, apps.MaxReducers-1)
+	}
+	app, err := apps.New(req.Mode, req.Params)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Invalid mode"}, err
+	}
+	// The output is compressed here rather than by the format, to count the
+	// bytes on both sides of the codec.
+	outputFormat, outputCodec := formats.CutCodec(req.OutputFormat)
+	format, err := formats.LookupOutput(outputFormat)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Invalid output format"}, err
+	}
+	if f, ok := format.(formats.JSONLinesOutput); ok {
+		if jv, ok := app.(apps.JSONValuer); ok {
+			f.JSONValues = jv.JSONValues()
+			format = f
+		}
+	}
+	codec, err := formats.LookupCodec(req.IntermediateCodec)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Invalid intermediate codec"}, err
+	}
+
+	// The output directory is on the filesystem the input is on.
+	outputDir := orDefault(req.OutputDir, "output")
+	if err := security.CheckPath(w.InputRoot, outputDir); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Output directory not allowed"}, err
+	}
+	dir, err := w.intermediateDir(req.IntermediateDir)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Intermediate directory not allowed"}, err
+	}
+
+	key := taskKey{req.JobId, pb.TaskType_REDUCE_TASK, req.ReduceTaskId, req.Attempt}
+	fetchDir := filepath.Join(dir, fmt.Sprintf("reduce-%d-attempt-%d", req.ReduceTaskId, req.Attempt))
+	if err := os.MkdirAll(fetchDir, os.ModePerm); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to create intermediate directory"}, err
+	}
+	defer os.RemoveAll(fetchDir)
+	lost, err := w.fetchPartitions(ctx, req, fetchDir, func(p float64) { w.setProgress(key, 0.5*p) })
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to fetch intermediate data", LostMapOutputs: lost}, err
+	}
+	counters, appCounters := apps.Counters{}, apps.Counters{}
+	budget := w.TaskMemory
+	if budget <= 0 {
+		budget = DefaultTaskMemory
+	}
+	runs, mergePeak, err := mergeRuns(ctx, fetchDir, partitionFiles(fetchDir, req.ReduceTaskId, req.MapAttempts), mergeFanIn(budget), codec, counters, apps.ReduceSpilledRecords)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to merge intermediate files"}, err
+	}
+	input, err := openRuns(runs, codec)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to read intermediate file"}, err
+	}
+	defer input.Close()
+
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to create output directory"}, err
+	}
+	outputName := filepath.Join(outputDir, fmt.Sprintf("out-%d-attempt-%d%s%s", req.ReduceTaskId, req.Attempt, format.Ext(), outputCodec.Ext()))
+	outputFile, err := createAtomic(outputName)
+	if err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to create output file"}, err
+	}
+	defer outputFile.Abort()
+	var combine func(key string, values []string) []string
+	if c, ok := app.(apps.Combiner); ok {
+		combine = c.Combine
+	}
+	reduceFunc := func(key string, values []string) []apps.KeyValue {
+		return []apps.KeyValue{{Key: key, Value: app.Reduce(key, values)}}
+	}
+	if cr, ok := app.(apps.CountingReducer); ok {
+		reduceFunc = func(key string, values []string) []apps.KeyValue {
+			return []apps.KeyValue{{Key: key, Value: cr.ReduceWithCounters(key, values, appCounters)}}
+		}
+	}
+	if rr, ok := app.(apps.RecordReducer); ok {
+		reduceFunc = rr.ReduceRecords
+	}
+	// reduceNext reduces the next key and passes the pairs to write to emit.
+	// ok is false once every key was reduced.
+	reduceNext := func(emit func(apps.KeyValue)) (ok bool, err error) {
+		k, values, ok, err := input.NextGroup(valueLimit(budget), combine)
+		if !ok || err != nil {
+			return false, err
+		}
+		for _, kv := range reduceFunc(k, values) {
+			emit(kv)
+		}
+		return true, nil
+	}
+	if sr, ok := app.(apps.StreamReducer); ok {
+		reduceNext = func(emit func(apps.KeyValue)) (bool, error) {
+			k, ok := input.NextKey()
+			if !ok {
+				return false, nil
+			}
+			values := input.Values(k)
+			sr.ReduceStream(k, values, emit)
+			for range values {
+				// Skip the values the app did not read.
+			}
+			return true, input.Err()
+		}
+	}
+	compressed := &countingWriter{w: outputFile}
+	zw := outputCodec.NewWriter(compressed)
+	uncompressed := &countingWriter{w: zw}
+	buf := bufio.NewWriter(uncompressed)
+	out := format.NewWriter(buf)
+	var writeErr error
+	emit := func(kv apps.KeyValue) {
+		if writeErr == nil {
+			if writeErr = out.Write(kv.Key, kv.Value); writeErr == nil {
+				counters.Add(apps.ReduceOutputRecords, 1)
+			}
+		}
+	}
+	for groups := 0; ; groups++ {
+		ok, err := reduceNext(emit)
+		if err != nil {
+			return &pb.TaskResponse{Success: false, Message: "Failed to read intermediate file"}, err
+		}
+		if writeErr != nil {
+			return &pb.TaskResponse{Success: false, Message: "Failed to write output file"}, writeErr
+		}
+		if !ok {
+			break
+		}
+		counters.Add(apps.ReduceInputGroups, 1)
+		if groups%1000 == 0 {
+			if ctx.Err() != nil {
+				return &pb.TaskResponse{Success: false, Message: "Reduce task aborted"}, ctx.Err()
+			}
+			w.setProgress(key, 0.5+0.5*input.Progress())
+		}
+	}
+	if err := out.Close(); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to write output file"}, err
+	}
+	if err := buf.Flush(); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to write output file"}, err
+	}
+	if err := zw.Close(); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to write output file"}, err
+	}
+	if ctx.Err() != nil {
+		return &pb.TaskResponse{Success: false, Message: "Reduce task aborted"}, ctx.Err()
+	}
+	if err := outputFile.Commit(); err != nil {
+		return &pb.TaskResponse{Success: false, Message: "Failed to commit output file"}, err
+	}
+	counters.Add(apps.ReduceInputRecords, input.Records())
+	counters.AddAll(apps.AppCounterPrefix, appCounters)
+	resp := &pb.TaskResponse{
+		Success:           true,
+		Message:           "Reduce task completed",
+		OutputFile:        outputName,
+		Counters:          counters,
+		BufferedBytesPeak: max(mergePeak, input.Peak()),
+	}
+	if outputCodec.Name() != formats.None {
+		resp.UncompressedBytes, resp.CompressedBytes = uncompressed.n, compressed.n
+	}
+	return resp, nil
+}
+
+// intermediateDir returns the local path of a job's intermediate directory,
+// which must be inside w.Dir. Map output stays on the worker that wrote it,
+// so every worker has its own.
+func (w *Worker) intermediateDir(dir string) (string, error) {
+	path := filepath.Join(w.Dir, orDefault(dir, "intermediate"))
+	if err := security.CheckPath(orDefault(w.Dir, "."), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// openSplit opens length bytes of a file starting at offset, or everything
+// from offset on if length is 0.
+func openSplit(filename string, offset, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	if length == 0 {
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		length = info.Size() - offset
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.NewSectionReader(f, offset, length), f}, nil
+}